@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/config"
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/contentful"
+	"github.com/spf13/cobra"
+)
+
+var buildLogServiceFlag string
+var buildLogKeepFlag int
+var buildLogDryRunFlag bool
+
+var buildLogCmd = &cobra.Command{
+	Use:   "buildlog",
+	Short: "Inspect and maintain the shared build log",
+}
+
+var buildLogListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List build log entries",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadContentful(configOverrides())
+		if err != nil {
+			return withCode(codeConfig, fmt.Errorf("config: %w", err))
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		client := newContentfulClient(cfg)
+		result, err := client.GetBuildLog(ctx)
+		if err != nil {
+			return withCode(codeContentful, fmt.Errorf("fetch: %w", err))
+		}
+
+		n := 0
+		for _, e := range result.Entries {
+			if buildLogServiceFlag != "" && e.Service != buildLogServiceFlag {
+				continue
+			}
+			n++
+			fmt.Printf("%s  %-28s %-15s section=%s status=%s new=%d total=%d force=%t translate=%t\n",
+				e.Timestamp, e.Service, e.TriggeredBy, e.SectionID, e.Status,
+				e.NewAdded, e.TotalAfterSync, e.ForceUpdate, e.TranslationUsed)
+		}
+
+		if n == 0 {
+			fmt.Println("No build log entries found.")
+		}
+		return nil
+	},
+}
+
+var buildLogPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Trim build log entries to the most recent N per service and section",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if buildLogKeepFlag < 0 {
+			return withCode(codeUsage, fmt.Errorf("--keep must be zero or greater"))
+		}
+
+		cfg, err := config.LoadContentful(configOverrides())
+		if err != nil {
+			return withCode(codeConfig, fmt.Errorf("config: %w", err))
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		client := newContentfulClient(cfg)
+		result, err := client.GetBuildLog(ctx)
+		if err != nil {
+			return withCode(codeContentful, fmt.Errorf("fetch: %w", err))
+		}
+		if result.EntryID == "" {
+			fmt.Println("No build log entry exists.")
+			return nil
+		}
+
+		kept := trimBuildLog(result.Entries, buildLogServiceFlag, buildLogKeepFlag)
+		removed := len(result.Entries) - len(kept)
+		if removed == 0 {
+			fmt.Println("Nothing to prune.")
+			return nil
+		}
+
+		if buildLogDryRunFlag {
+			fmt.Printf("Would remove %d of %d entries (dry run).\n", removed, len(result.Entries))
+			return nil
+		}
+
+		version, err := client.UpdateBuildLog(ctx, result, kept)
+		if err != nil {
+			return withCode(codeContentful, fmt.Errorf("update: %w", err))
+		}
+		if err := client.PublishEntry(ctx, result.EntryID, version); err != nil {
+			return withCode(codeContentful, fmt.Errorf("publish: %w", err))
+		}
+
+		fmt.Printf("Removed %d of %d entries.\n", removed, len(result.Entries))
+		return nil
+	},
+}
+
+// trimBuildLog keeps only the most recent keep entries of each service and
+// section, preserving the original order. When service is non-empty only that
+// service's entries are trimmed and all others are kept untouched.
+func trimBuildLog(entries []contentful.BuildLogEntry, service string, keep int) []contentful.BuildLogEntry {
+	key := func(e contentful.BuildLogEntry) string { return e.Service + "|" + e.SectionID }
+	remaining := make(map[string]int)
+	for _, e := range entries {
+		remaining[key(e)]++
+	}
+
+	var kept []contentful.BuildLogEntry
+	for _, e := range entries {
+		if service != "" && e.Service != service {
+			kept = append(kept, e)
+			continue
+		}
+		if remaining[key(e)] <= keep {
+			kept = append(kept, e)
+		}
+		remaining[key(e)]--
+	}
+	return kept
+}
+
+func init() {
+	buildLogCmd.PersistentFlags().StringVar(&buildLogServiceFlag, "service", "", "Only consider entries for this service")
+	buildLogPruneCmd.Flags().IntVar(&buildLogKeepFlag, "keep", 3, "Number of most recent entries to keep per service")
+	buildLogPruneCmd.Flags().BoolVar(&buildLogDryRunFlag, "dry-run", false, "Report what would be removed without writing")
+	buildLogCmd.AddCommand(buildLogListCmd, buildLogPruneCmd)
+	rootCmd.AddCommand(buildLogCmd)
+}
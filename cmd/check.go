@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	gosync "sync"
+	"time"
+
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/config"
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/httpx"
+	"github.com/spf13/cobra"
+)
+
+var checkConcurrencyFlag int
+var checkTimeoutFlag time.Duration
+
+// linkCheck is the outcome of checking one URL of one testimonial.
+type linkCheck struct {
+	name   string
+	field  string
+	url    string
+	status int
+	err    error
+}
+
+func (c linkCheck) broken() bool {
+	// LinkedIn answers unauthenticated clients with 999; that says nothing
+	// about whether the profile exists.
+	return c.err != nil || (c.status >= 400 && c.status != 999)
+}
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check testimonial avatar and LinkedIn URLs for dead links",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadContentful(configOverrides())
+		if err != nil {
+			return withCode(codeConfig, fmt.Errorf("config: %w", err))
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+
+		client := newContentfulClient(cfg)
+		result, err := client.GetTestimonials(ctx)
+		if err != nil {
+			return withCode(codeContentful, fmt.Errorf("fetch: %w", err))
+		}
+
+		var checks []linkCheck
+		for _, t := range result.Testimonials {
+			if t.AvatarURL != "" {
+				checks = append(checks, linkCheck{name: t.Name, field: "avatar", url: t.AvatarURL})
+			}
+			if t.LinkedInURL != "" {
+				checks = append(checks, linkCheck{name: t.Name, field: "linkedin", url: t.LinkedInURL})
+			}
+		}
+
+		runLinkChecks(ctx, httpx.NewClient(checkTimeoutFlag), checks, checkConcurrencyFlag)
+
+		broken := 0
+		for _, c := range checks {
+			if !c.broken() {
+				continue
+			}
+			broken++
+			if c.err != nil {
+				fmt.Printf("BROKEN  %-30s %-8s %s (%v)\n", c.name, c.field, c.url, c.err)
+			} else {
+				fmt.Printf("BROKEN  %-30s %-8s %s (HTTP %d)\n", c.name, c.field, c.url, c.status)
+			}
+		}
+		fmt.Printf("Checked %d URLs across %d testimonials: %d broken\n", len(checks), len(result.Testimonials), broken)
+
+		if broken > 0 {
+			return withCode(codeContentful, fmt.Errorf("%d broken links", broken))
+		}
+		return nil
+	},
+}
+
+// runLinkChecks fills in the status of each check using at most concurrency
+// parallel requests. Checks keep their order.
+func runLinkChecks(ctx context.Context, client *http.Client, checks []linkCheck, concurrency int) {
+	sem := make(chan struct{}, max(concurrency, 1))
+	var wg gosync.WaitGroup
+	for i := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			checks[i].status, checks[i].err = checkURL(ctx, client, checks[i].url)
+		}()
+	}
+	wg.Wait()
+}
+
+// checkURL returns the status of a HEAD request, retrying with GET when the
+// server doesn't support HEAD.
+func checkURL(ctx context.Context, client *http.Client, url string) (int, error) {
+	status, err := requestStatus(ctx, client, "HEAD", url)
+	if err == nil && status == http.StatusMethodNotAllowed {
+		return requestStatus(ctx, client, "GET", url)
+	}
+	return status, err
+}
+
+func requestStatus(ctx context.Context, client *http.Client, method, url string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+func init() {
+	checkCmd.Flags().IntVar(&checkConcurrencyFlag, "concurrency", 8, "Number of URLs to check in parallel")
+	checkCmd.Flags().DurationVar(&checkTimeoutFlag, "timeout", 10*time.Second, "Per-request timeout")
+	rootCmd.AddCommand(checkCmd)
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/config"
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/sync"
+	"github.com/spf13/cobra"
+)
+
+var dedupeAllowEmptyCompanyFlag bool
+var dedupeDryRunFlag bool
+
+var dedupeCmd = &cobra.Command{
+	Use:   "dedupe",
+	Short: "Remove duplicate testimonials already stored in Contentful",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadContentful(configOverrides())
+		if err != nil {
+			return withCode(codeConfig, fmt.Errorf("config: %w", err))
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		client := newContentfulClient(cfg)
+		result, err := client.GetTestimonials(ctx)
+		if err != nil {
+			return withCode(codeContentful, fmt.Errorf("fetch: %w", err))
+		}
+
+		dups := sync.FindDuplicates(result.Testimonials, dedupeAllowEmptyCompanyFlag)
+		if len(dups) == 0 {
+			fmt.Println("No duplicates found.")
+			return nil
+		}
+
+		for _, d := range dups {
+			keep, drop := result.Testimonials[d.Keep], result.Testimonials[d.Drop]
+			note := ""
+			if d.EmptyCompany {
+				note = " (empty company)"
+			}
+			fmt.Printf("  - %s @ %q duplicates %s @ %q%s\n", drop.Name, drop.Company, keep.Name, keep.Company, note)
+		}
+
+		if dedupeDryRunFlag {
+			fmt.Printf("Would remove %d of %d testimonials (dry run).\n", len(dups), len(result.Testimonials))
+			return nil
+		}
+
+		deduped := sync.RemoveDuplicates(result.Testimonials, dups)
+		version, err := client.UpdateTestimonials(ctx, result, deduped)
+		if err != nil {
+			return withCode(codeContentful, fmt.Errorf("update: %w", err))
+		}
+		if err := client.PublishEntry(ctx, result.EntryID, version); err != nil {
+			return withCode(codeContentful, fmt.Errorf("publish: %w", err))
+		}
+
+		fmt.Printf("Removed %d of %d testimonials.\n", len(result.Testimonials)-len(deduped), len(result.Testimonials))
+		return nil
+	},
+}
+
+func init() {
+	dedupeCmd.Flags().BoolVar(&dedupeAllowEmptyCompanyFlag, "allow-empty-company", false, "Also merge same-name pairs where one entry has no company")
+	dedupeCmd.Flags().BoolVar(&dedupeDryRunFlag, "dry-run", false, "Show duplicates without writing")
+	rootCmd.AddCommand(dedupeCmd)
+}
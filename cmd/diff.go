@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/config"
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/contentful"
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/linkedin"
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/sync"
+	"github.com/spf13/cobra"
+)
+
+var diffProfileFlag string
+var diffBaselineFlag string
+var diffJSONFlag bool
+
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show what a sync would change without writing anything",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if diffProfileFlag == "" {
+			return withCode(codeUsage, fmt.Errorf("--profile flag is required"))
+		}
+		if err := validateDedupeBy(); err != nil {
+			return err
+		}
+
+		// With a baseline file no Contentful access is needed.
+		var cfg *config.Config
+		var err error
+		if diffBaselineFlag != "" {
+			cfg, err = config.LoadLinkedIn(configOverrides())
+		} else {
+			cfg, err = config.Load(readOnlyOverrides())
+		}
+		if err != nil {
+			return withCode(codeConfig, fmt.Errorf("config: %w", err))
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+
+		var existing []contentful.Testimonial
+		if diffBaselineFlag != "" {
+			existing, err = readTestimonialsFile(diffBaselineFlag)
+			if err != nil {
+				return withCode(codeUsage, fmt.Errorf("baseline: %w", err))
+			}
+		} else {
+			result, err := newReadClient(cfg).GetTestimonials(ctx)
+			if err != nil {
+				return withCode(codeContentful, fmt.Errorf("contentful fetch: %w", err))
+			}
+			existing = result.Testimonials
+		}
+
+		logger.Info("Scraping LinkedIn recommendations")
+		scraped, _, err := linkedin.Scrape(ctx, diffProfileFlag, cfg.LinkedInCookie, linkedin.Options{
+			Verbose:         verbose,
+			Logger:          logger,
+			BaseURL:         cfg.VoyagerBaseURL,
+			ProtocolVersion: cfg.RestliProtocolVersion,
+		})
+		if err != nil {
+			return scrapeError(err)
+		}
+
+		diff := sync.Diff(existing, scraped, sync.MergeOptions{
+			Strategy: sync.DedupeStrategy(dedupeByFlag),
+		})
+
+		if diffJSONFlag {
+			if err := writeDiffJSON(diff); err != nil {
+				return withCode(codeInternal, fmt.Errorf("write JSON: %w", err))
+			}
+			return nil
+		}
+
+		fmt.Printf("Would add (%d):\n", len(diff.Added))
+		for _, t := range diff.Added {
+			fmt.Printf("  + %s — %s @ %s\n", t.Name, t.Role, t.Company)
+		}
+		fmt.Printf("Would update (%d):\n", len(diff.Changed))
+		for _, c := range diff.Changed {
+			fmt.Printf("  ~ %s — %s\n", c.Existing.Name, strings.Join(c.Fields, ", "))
+			if c.Existing.Role != c.Role || c.Existing.Company != c.Company {
+				fmt.Printf("      %s @ %s -> %s @ %s\n", c.Existing.Role, c.Existing.Company, c.Role, c.Company)
+			}
+		}
+		fmt.Printf("Only in existing (%d):\n", len(diff.OnlyExisting))
+		for _, t := range diff.OnlyExisting {
+			fmt.Printf("  ? %s — %s @ %s\n", t.Name, t.Role, t.Company)
+		}
+		return nil
+	},
+}
+
+// writeDiffJSON prints diff to stdout as indented JSON, with empty
+// categories as [] rather than null.
+func writeDiffJSON(diff sync.DiffResult) error {
+	if diff.Added == nil {
+		diff.Added = []contentful.Testimonial{}
+	}
+	if diff.Changed == nil {
+		diff.Changed = []sync.Change{}
+	}
+	if diff.OnlyExisting == nil {
+		diff.OnlyExisting = []contentful.Testimonial{}
+	}
+	data, err := json.MarshalIndent(diff, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(os.Stdout, string(data))
+	return err
+}
+
+// readTestimonialsFile reads a JSON array of testimonials from path.
+func readTestimonialsFile(path string) ([]contentful.Testimonial, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var testimonials []contentful.Testimonial
+	if err := json.Unmarshal(data, &testimonials); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return testimonials, nil
+}
+
+func init() {
+	diffCmd.Flags().StringVar(&diffProfileFlag, "profile", "", "LinkedIn username (e.g. alberthiggs)")
+	diffCmd.Flags().StringVar(&diffBaselineFlag, "baseline", "", "Compare against a local testimonials JSON file instead of Contentful")
+	diffCmd.Flags().BoolVar(&diffJSONFlag, "json", false, "Print the diff as JSON with added, changed and onlyExisting lists")
+	diffCmd.Flags().StringVar(&dedupeByFlag, "dedupe-by", string(sync.DedupeNameCompany), "Dedupe key: name (name + company) or slug (LinkedIn profile slug)")
+	rootCmd.AddCommand(diffCmd)
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Error codes reported in JSON error output. Each maps to a process exit status.
+const (
+	codeInternal   = "internal"
+	codeUsage      = "usage"
+	codeConfig     = "config"
+	codeLinkedIn   = "linkedin"
+	codeContentful = "contentful"
+	codeTranslate  = "translate"
+	codeValidation = "validation"
+	// codePartial means scrape synced and published, but some items failed
+	// along the way, e.g. avatar uploads or translations.
+	codePartial = "partial"
+	// codeNoChange is not a failure: scrape returns it with
+	// --exit-code-on-nochange so CI can skip downstream steps.
+	codeNoChange = "nochange"
+)
+
+var exitCodes = map[string]int{
+	codeInternal:   1,
+	codeUsage:      2,
+	codeConfig:     3,
+	codeLinkedIn:   4,
+	codeContentful: 5,
+	codeTranslate:  6,
+	codeValidation: 7,
+	codePartial:    8,
+	codeNoChange:   78, // the status Drone and similar CIs treat as "skip the remaining steps"
+}
+
+// errNoChanges reports a successful scrape that wrote nothing.
+var errNoChanges = withCode(codeNoChange, errors.New("no changes to sync"))
+
+// codedError tags an error with a stable code for scripts and CI.
+type codedError struct {
+	code string
+	err  error
+}
+
+func (e *codedError) Error() string { return e.err.Error() }
+func (e *codedError) Unwrap() error { return e.err }
+
+// withCode wraps err with the given error code. A nil err stays nil.
+func withCode(code string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &codedError{code: code, err: err}
+}
+
+// errorCode returns the code attached to err, or codeInternal if none.
+func errorCode(err error) string {
+	var ce *codedError
+	if errors.As(err, &ce) {
+		return ce.code
+	}
+	return codeInternal
+}
+
+// exitCodeFor returns the process exit status for err.
+func exitCodeFor(err error) int {
+	if code, ok := exitCodes[errorCode(err)]; ok {
+		return code
+	}
+	return 1
+}
+
+// writeErrorJSON writes the machine-readable error envelope for err.
+func writeErrorJSON(w io.Writer, err error) {
+	type errorBody struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+	envelope := struct {
+		Status string    `json:"status"`
+		Error  errorBody `json:"error"`
+	}{
+		Status: "error",
+		Error:  errorBody{Code: errorCode(err), Message: err.Error()},
+	}
+	out, mErr := json.Marshal(envelope)
+	if mErr != nil {
+		fmt.Fprintln(w, err)
+		return
+	}
+	fmt.Fprintln(w, string(out))
+}
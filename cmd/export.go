@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/config"
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/contentful"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var exportFileFlag string
+var exportFormatFlag string
+var prettyFlag bool
+var exportMetadataFlag bool
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export current testimonials from Contentful",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch exportFormatFlag {
+		case "json", "ndjson", "yaml":
+		default:
+			return withCode(codeUsage, fmt.Errorf("--format must be json, ndjson or yaml, got %q", exportFormatFlag))
+		}
+		if exportMetadataFlag && exportFormatFlag != "json" {
+			return withCode(codeUsage, fmt.Errorf("--metadata requires --format=json"))
+		}
+
+		cfg, err := config.LoadContentful(configOverrides())
+		if err != nil {
+			return withCode(codeConfig, fmt.Errorf("config: %w", err))
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		client := newContentfulClient(cfg)
+		result, err := client.GetTestimonials(ctx)
+		if err != nil {
+			return withCode(codeContentful, fmt.Errorf("fetch: %w", err))
+		}
+		if result.EntryID == "" {
+			return withCode(codeContentful, fmt.Errorf("no testimonials entry for section %q; nothing to export", client.SectionID))
+		}
+
+		var w io.Writer = os.Stdout
+		var f *os.File
+		if exportFileFlag != "" && exportFileFlag != "-" {
+			f, err = os.Create(exportFileFlag)
+			if err != nil {
+				return withCode(codeInternal, fmt.Errorf("create file: %w", err))
+			}
+			defer f.Close()
+			w = f
+		}
+
+		if exportMetadataFlag {
+			err = writeSnapshot(w, exportSnapshot{
+				EntryID:      result.EntryID,
+				Version:      result.Version,
+				SectionID:    client.SectionID,
+				ExportedAt:   time.Now().UTC().Format(time.RFC3339),
+				Testimonials: result.Testimonials,
+			})
+		} else {
+			err = writeTestimonials(w, exportFormatFlag, result.Testimonials)
+		}
+		if err != nil {
+			return withCode(codeInternal, fmt.Errorf("write: %w", err))
+		}
+
+		if f != nil {
+			if err := f.Close(); err != nil {
+				return withCode(codeInternal, fmt.Errorf("close file: %w", err))
+			}
+			logger.Info("Exported testimonials", "count", len(result.Testimonials), "file", exportFileFlag)
+		}
+		return nil
+	},
+}
+
+// exportSnapshot is the --metadata export: the testimonials together with
+// the entry they were read from, for backups and moving between spaces.
+type exportSnapshot struct {
+	EntryID      string                   `json:"entryId"`
+	Version      int                      `json:"version"`
+	SectionID    string                   `json:"sectionId"`
+	ExportedAt   string                   `json:"exportedAt"`
+	Testimonials []contentful.Testimonial `json:"testimonials"`
+}
+
+// writeSnapshot encodes snap as JSON, indented with --pretty.
+func writeSnapshot(w io.Writer, snap exportSnapshot) error {
+	if snap.Testimonials == nil {
+		snap.Testimonials = []contentful.Testimonial{}
+	}
+	enc := json.NewEncoder(w)
+	if prettyFlag {
+		enc.SetIndent("", "  ")
+	}
+	return enc.Encode(snap)
+}
+
+// writeTestimonials encodes testimonials in the given export format.
+// ndjson writes one complete JSON object per line and is never indented.
+func writeTestimonials(w io.Writer, format string, testimonials []contentful.Testimonial) error {
+	enc := json.NewEncoder(w)
+	switch format {
+	case "yaml":
+		return writeYAML(w, testimonials)
+	case "ndjson":
+		for _, t := range testimonials {
+			if err := enc.Encode(t); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		if testimonials == nil {
+			testimonials = []contentful.Testimonial{}
+		}
+		if prettyFlag {
+			enc.SetIndent("", "  ")
+		}
+		return enc.Encode(testimonials)
+	}
+}
+
+// writeYAML writes testimonials as a YAML list for static-site data
+// directories (Hugo data/, Jekyll _data/). Keys and omitted fields follow
+// the JSON encoding; multi-line quotes use literal block scalars.
+func writeYAML(w io.Writer, testimonials []contentful.Testimonial) error {
+	if testimonials == nil {
+		testimonials = []contentful.Testimonial{}
+	}
+	data, err := json.Marshal(testimonials)
+	if err != nil {
+		return err
+	}
+	// JSON is valid YAML, so decoding it into a node keeps the key order.
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	setBlockStyle(&doc)
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+var yaml11Bools = map[string]bool{
+	"y": true, "n": true, "yes": true, "no": true, "on": true, "off": true, "true": true, "false": true,
+}
+
+// setBlockStyle drops the flow and quoting styles inherited from JSON and
+// renders multi-line strings as literal blocks. Strings YAML 1.1 parsers
+// (Jekyll) would read as booleans stay quoted.
+func setBlockStyle(n *yaml.Node) {
+	n.Style = 0
+	if n.Kind == yaml.ScalarNode && n.Tag == "!!str" {
+		switch {
+		case strings.Contains(n.Value, "\n"):
+			n.Style = yaml.LiteralStyle
+		case yaml11Bools[strings.ToLower(n.Value)]:
+			n.Style = yaml.DoubleQuotedStyle
+		}
+	}
+	for _, c := range n.Content {
+		setBlockStyle(c)
+	}
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportFileFlag, "file", "", "Output file (default stdout)")
+	exportCmd.Flags().StringVar(&exportFormatFlag, "format", "json", "Output format: json, ndjson or yaml")
+	exportCmd.Flags().BoolVar(&prettyFlag, "pretty", true, "Indent --format json output (--pretty=false writes it on one line)")
+	exportCmd.Flags().BoolVar(&exportMetadataFlag, "metadata", false, "Wrap the testimonials in an object with the entry ID, version and section (JSON only)")
+	rootCmd.AddCommand(exportCmd)
+}
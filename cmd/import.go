@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/config"
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/contentful"
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/linkedin"
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/sync"
+	"github.com/spf13/cobra"
+)
+
+var importFileFlag string
+var importSectionIDFlag string
+var importForceFlag bool
+var importDedupeByFlag string
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Create or update testimonials from a JSON file",
+	Long: "Reads testimonials from a JSON array or an `export --metadata` snapshot and " +
+		"merges them into the section entry with the same dedupe rules as scrape. " +
+		"Use --force to replace the stored testimonials instead.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if importFileFlag == "" {
+			return withCode(codeUsage, fmt.Errorf("--file is required"))
+		}
+		switch sync.DedupeStrategy(importDedupeByFlag) {
+		case sync.DedupeNameCompany, sync.DedupeSlug:
+		default:
+			return withCode(codeUsage, fmt.Errorf("--dedupe-by must be name or slug, got %q", importDedupeByFlag))
+		}
+
+		cfg, err := config.LoadContentful(configOverrides())
+		if err != nil {
+			return withCode(codeConfig, fmt.Errorf("config: %w", err))
+		}
+
+		testimonials, err := readImportFile(importFileFlag)
+		if err != nil {
+			return withCode(codeUsage, fmt.Errorf("read %s: %w", importFileFlag, err))
+		}
+
+		var valid []contentful.Testimonial
+		for i, t := range testimonials {
+			if strings.TrimSpace(t.Name) == "" || strings.TrimSpace(t.Quote) == "" {
+				logger.Warn("Skipping record: name and quote are required", "record", i+1)
+				continue
+			}
+			valid = append(valid, t)
+		}
+		invalid := len(testimonials) - len(valid)
+		if len(valid) == 0 {
+			return withCode(codeValidation, fmt.Errorf("%s has no valid testimonials (%d skipped)", importFileFlag, invalid))
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		client := newContentfulClient(cfg)
+		if importSectionIDFlag != "" {
+			client.SectionID = importSectionIDFlag
+		}
+		result, err := client.GetTestimonials(ctx)
+		if err != nil {
+			return withCode(codeContentful, fmt.Errorf("fetch: %w", err))
+		}
+
+		var merged []contentful.Testimonial
+		var imported int
+		if importForceFlag {
+			merged = valid
+			imported = len(valid)
+		} else {
+			recs := make([]linkedin.Recommendation, len(valid))
+			for i, t := range valid {
+				recs[i] = sync.FromTestimonial(t)
+			}
+			var newIndices []int
+			merged, newIndices, _ = sync.Merge(result.Testimonials, recs, sync.MergeOptions{
+				Strategy: sync.DedupeStrategy(importDedupeByFlag),
+			})
+			imported = len(newIndices)
+		}
+		skipped := len(testimonials) - imported
+
+		if imported == 0 && !importForceFlag {
+			fmt.Printf("Nothing to import: 0 imported, %d skipped (%d invalid, %d already stored).\n",
+				skipped, invalid, skipped-invalid)
+			return nil
+		}
+
+		var entryID string
+		var version int
+		if result.EntryID == "" {
+			entryID, version, err = client.CreateTestimonials(ctx, merged)
+			if err != nil {
+				return withCode(codeContentful, fmt.Errorf("create: %w", err))
+			}
+		} else {
+			entryID = result.EntryID
+			version, err = client.UpdateTestimonials(ctx, result, merged)
+			if err != nil {
+				return withCode(codeContentful, fmt.Errorf("update: %w", err))
+			}
+		}
+		if err := client.PublishEntry(ctx, entryID, version); err != nil {
+			return withCode(codeContentful, fmt.Errorf("publish: %w", err))
+		}
+
+		fmt.Printf("Imported %d, skipped %d (%d invalid, %d already stored); entry %s now has %d testimonials.\n",
+			imported, skipped, invalid, skipped-invalid, entryID, len(merged))
+		return nil
+	},
+}
+
+// readImportFile reads testimonials from a JSON array or an export
+// snapshot object.
+func readImportFile(path string) ([]contentful.Testimonial, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSpace(data)
+
+	if bytes.HasPrefix(data, []byte("{")) {
+		var snap exportSnapshot
+		if err := json.Unmarshal(data, &snap); err != nil {
+			return nil, err
+		}
+		return snap.Testimonials, nil
+	}
+	var testimonials []contentful.Testimonial
+	if err := json.Unmarshal(data, &testimonials); err != nil {
+		return nil, err
+	}
+	return testimonials, nil
+}
+
+func init() {
+	importCmd.Flags().StringVar(&importFileFlag, "file", "", "JSON file to import (required)")
+	importCmd.Flags().StringVar(&importSectionIDFlag, "section-id", "", "siteSection sectionId to import into (default CONTENTFUL_SECTION_ID or "+contentful.DefaultSectionID+")")
+	importCmd.Flags().BoolVar(&importForceFlag, "force", false, "Replace the stored testimonials with the file's instead of merging")
+	importCmd.Flags().StringVar(&importDedupeByFlag, "dedupe-by", string(sync.DedupeNameCompany), "Dedupe key: name (name + company) or slug (LinkedIn profile slug)")
+	rootCmd.AddCommand(importCmd)
+}
@@ -2,30 +2,63 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"sort"
 	"time"
 
 	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/config"
 	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/contentful"
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/feed"
 	"github.com/spf13/cobra"
 )
 
+var feedOutFlag string
+var feedTitleFlag string
+var feedLinkFlag string
+var listJSONFlag bool
+var listFileFlag string
+
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List current testimonials in Contentful",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, err := config.LoadContentful()
+		cfg, err := config.LoadContentful(readOnlyOverrides())
 		if err != nil {
-			return fmt.Errorf("config: %w", err)
+			return withCode(codeConfig, fmt.Errorf("config: %w", err))
 		}
 
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
-		client := contentful.NewClient(cfg.SpaceID, cfg.CMAToken)
+		client := newReadClient(cfg)
+		if cma, ok := client.(*contentful.Client); ok && !skipPreflightFlag {
+			if err := verifyAccess(ctx, cma); err != nil {
+				return err
+			}
+		}
 		result, err := client.GetTestimonials(ctx)
 		if err != nil {
-			return fmt.Errorf("fetch: %w", err)
+			return withCode(codeContentful, fmt.Errorf("fetch: %w", err))
+		}
+		logFieldLocales(result.FieldLocales)
+
+		if feedOutFlag != "" {
+			if err := writeFeed(feedOutFlag, result.Testimonials); err != nil {
+				return withCode(codeInternal, fmt.Errorf("feed: %w", err))
+			}
+			logger.Info("Wrote RSS feed", "items", len(result.Testimonials), "file", feedOutFlag)
+		}
+
+		if listJSONFlag || listFileFlag != "" {
+			if err := writeListJSON(listFileFlag, result.Testimonials); err != nil {
+				return withCode(codeInternal, fmt.Errorf("write JSON: %w", err))
+			}
+			if listFileFlag != "" {
+				logger.Info("Wrote testimonials", "count", len(result.Testimonials), "file", listFileFlag)
+			}
+			return nil
 		}
 
 		if len(result.Testimonials) == 0 {
@@ -45,6 +78,62 @@ var listCmd = &cobra.Command{
 	},
 }
 
+// logFieldLocales logs at debug level which locale each entry field was
+// read from.
+func logFieldLocales(fieldLocales map[string]string) {
+	names := make([]string, 0, len(fieldLocales))
+	for name := range fieldLocales {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		logger.Debug("Field read from locale", "field", name, "locale", fieldLocales[name])
+	}
+}
+
+// writeListJSON writes testimonials as an indented JSON array to path, or
+// to stdout when path is empty.
+func writeListJSON(path string, testimonials []contentful.Testimonial) error {
+	if testimonials == nil {
+		testimonials = []contentful.Testimonial{}
+	}
+	data, err := json.MarshalIndent(testimonials, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if path == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// writeFeed writes testimonials as an RSS feed to path.
+func writeFeed(path string, testimonials []contentful.Testimonial) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	ch := feed.Channel{
+		Title:       feedTitleFlag,
+		Link:        feedLinkFlag,
+		Description: "Recommendations received on LinkedIn",
+	}
+	if err := feed.WriteRSS(f, ch, testimonials); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
 func init() {
+	listCmd.Flags().StringVar(&feedOutFlag, "feed-out", "", "Write testimonials as an RSS 2.0 feed to this path")
+	listCmd.Flags().StringVar(&feedTitleFlag, "feed-title", "Testimonials", "Title of the RSS feed")
+	listCmd.Flags().StringVar(&feedLinkFlag, "feed-link", "", "Site link of the RSS feed")
+	listCmd.Flags().BoolVar(&skipPreflightFlag, "skip-preflight", false, "Don't check the CMA token before fetching")
+	listCmd.Flags().BoolVar(&listJSONFlag, "json", false, "Print the full testimonials as a JSON array")
+	listCmd.Flags().StringVar(&listFileFlag, "file", "", "Write the JSON array to this path instead of stdout (implies --json)")
 	rootCmd.AddCommand(listCmd)
 }
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	gosync "sync"
+)
+
+// logOutput is where every log line goes. --silent-success swaps it for a
+// buffer while a run is in progress.
+var logOutput = &swapWriter{w: os.Stderr}
+
+// logger is the CLI's logger. It is rebuilt by setupLogging once flags are
+// parsed; until then it logs at info level.
+var logger = newLogger(false)
+
+// swapWriter is an io.Writer whose destination can be replaced while
+// other goroutines are writing to it.
+type swapWriter struct {
+	mu gosync.Mutex
+	w  io.Writer
+}
+
+func (s *swapWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(p)
+}
+
+// swap sets the destination to w and returns the previous one.
+func (s *swapWriter) swap(w io.Writer) io.Writer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev := s.w
+	s.w = w
+	return prev
+}
+
+// newLogger returns a text logger writing to logOutput at info level, or
+// at debug level when verbose is set.
+func newLogger(verbose bool) *slog.Logger {
+	level := slog.LevelInfo
+	if verbose {
+		level = slog.LevelDebug
+	}
+	return slog.New(slog.NewTextHandler(logOutput, &slog.HandlerOptions{Level: level}))
+}
+
+// setupLogging applies --verbose to logger and makes it the default, so
+// stray log.Printf calls share its level and output.
+func setupLogging() {
+	logger = newLogger(verbose)
+	slog.SetDefault(logger)
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/config"
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/contentful"
+	"github.com/spf13/cobra"
+)
+
+var publishSectionIDFlag string
+
+var publishCmd = &cobra.Command{
+	Use:   "publish",
+	Short: "Publish the current testimonials entry without scraping",
+	Long: "Publishes the latest version of the testimonials entry as it stands. " +
+		"Use it to recover when a sync wrote the entry but the publish step failed.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadContentful(configOverrides())
+		if err != nil {
+			return withCode(codeConfig, fmt.Errorf("config: %w", err))
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		client := newContentfulClient(cfg)
+		if publishSectionIDFlag != "" {
+			client.SectionID = publishSectionIDFlag
+		}
+		result, err := client.GetTestimonials(ctx)
+		if err != nil {
+			return withCode(codeContentful, fmt.Errorf("fetch: %w", err))
+		}
+		if result.EntryID == "" {
+			return withCode(codeContentful, fmt.Errorf("no testimonials entry found for section %q", client.SectionID))
+		}
+
+		// With --model references, linked testimonials written by a
+		// --no-publish run are drafts too; publish them first.
+		for _, le := range result.LinkedEntries {
+			if err := client.PublishEntry(ctx, le.ID, le.Version); err != nil {
+				return withCode(codeContentful, fmt.Errorf("publish testimonial %s: %w", le.Testimonial.Name, err))
+			}
+		}
+		if err := client.PublishEntry(ctx, result.EntryID, result.Version); err != nil {
+			return withCode(codeContentful, fmt.Errorf("publish: %w", err))
+		}
+
+		fmt.Printf("Published entry %s (version %d, %d testimonials).\n", result.EntryID, result.Version, len(result.Testimonials))
+		return nil
+	},
+}
+
+func init() {
+	publishCmd.Flags().StringVar(&publishSectionIDFlag, "section-id", "", "siteSection sectionId to publish (default CONTENTFUL_SECTION_ID or "+contentful.DefaultSectionID+")")
+	rootCmd.AddCommand(publishCmd)
+}
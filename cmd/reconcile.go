@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/sync"
+)
+
+// writeSummaryJSON writes the success envelope for --output json. Its
+// status is "partial" when any section had failed items.
+func writeSummaryJSON(w io.Writer, sections []*sync.Report) error {
+	if sections == nil {
+		sections = []*sync.Report{}
+	}
+	status := "ok"
+	for _, r := range sections {
+		if r.Failures() > 0 {
+			status = "partial"
+		}
+	}
+	out, err := json.Marshal(struct {
+		Status   string         `json:"status"`
+		Sections []*sync.Report `json:"sections"`
+	}{Status: status, Sections: sections})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(out))
+	return err
+}
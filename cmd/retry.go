@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/httpx"
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/linkedin"
+)
+
+// retryable reports whether a failed run is worth repeating: network
+// errors, timeouts, rate limits and 5xx responses from LinkedIn or
+// Contentful. Usage, config and validation errors, auth failures and an
+// exhausted request budget are not.
+func retryable(err error) bool {
+	switch errorCode(err) {
+	case codeUsage, codeConfig, codeValidation, codePartial:
+		return false
+	}
+	if errors.Is(err, linkedin.ErrRequestBudgetExceeded) {
+		return false
+	}
+	if errors.Is(err, linkedin.ErrRateLimited) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var statusErr *httpx.StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Temporary()
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// withRetries runs fn up to retries+1 times, waiting delay between
+// attempts, for as long as it fails with a retryable error.
+func withRetries(retries int, delay time.Duration, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt > retries || !retryable(err) {
+			return err
+		}
+		logger.Warn("Attempt failed; retrying", "attempt", attempt, "of", retries+1, "err", err, "delay", delay)
+		time.Sleep(delay)
+	}
+}
@@ -1,27 +1,159 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/config"
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/contentful"
 	"github.com/spf13/cobra"
 )
 
+// Version is the tool version, set at build time with
+// -ldflags "-X github.com/alberto-moreno-sa/linkedin-contentful-sync/cmd.Version=v1.2.3".
+var Version = "dev"
+
 var verbose bool
+var spaceFlag string
+var cmaTokenFlag string
+var outputFlag string
+var modelFlag string
+var cmaRetriesFlag int
+var configFileFlag string
+var tagsFlag []string
 
 var rootCmd = &cobra.Command{
 	Use:   "linkedin-sync",
 	Short: "Sync LinkedIn recommendations to Contentful",
 	Long:  "CLI tool that scrapes LinkedIn recommendations and syncs them to Contentful CMS.",
+	// Errors are reported by Execute so JSON mode can format them.
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		setupLogging()
+		switch outputFlag {
+		case "text":
+		case "json":
+			cmd.SilenceUsage = true
+		default:
+			return withCode(codeUsage, fmt.Errorf("--output must be text or json, got %q", outputFlag))
+		}
+		switch modelFlag {
+		case contentful.ModelEmbedded, contentful.ModelReferences:
+		default:
+			return withCode(codeUsage, fmt.Errorf("--model must be embedded or references, got %q", modelFlag))
+		}
+		return nil
+	},
 }
 
 func init() {
-	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	rootCmd.Version = Version
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Log debug details such as URNs, translations and avatar uploads")
+	rootCmd.PersistentFlags().StringVarP(&outputFlag, "output", "o", "text", "Output format: text or json")
+	rootCmd.PersistentFlags().StringVar(&modelFlag, "model", contentful.ModelEmbedded, "How the section stores testimonials: embedded (JSON array) or references (links to testimonial entries)")
+	rootCmd.PersistentFlags().IntVar(&cmaRetriesFlag, "cma-retries", contentful.DefaultMaxRetries, "Times to retry a Contentful request that is rate limited (429) or fails with a 5xx")
+	rootCmd.PersistentFlags().StringVar(&spaceFlag, "space", "", "Contentful space ID (overrides CONTENTFUL_SPACE_ID)")
+	rootCmd.PersistentFlags().StringVar(&cmaTokenFlag, "cma-token", "", "Contentful CMA token (overrides CONTENTFUL_CMA_TOKEN)")
+	rootCmd.PersistentFlags().StringSliceVar(&tagsFlag, "tag", nil, "ID of a Contentful tag to attach to written testimonials entries and avatars; repeatable. The tag must already exist")
+	rootCmd.PersistentFlags().StringVar(&configFileFlag, "config", "", "YAML or JSON config file; env vars override its values")
+}
+
+// configOverrides collects the global flags that take precedence over env vars.
+func configOverrides() config.Overrides {
+	return config.Overrides{
+		SpaceID:    spaceFlag,
+		CMAToken:   cmaTokenFlag,
+		ConfigFile: configFileFlag,
+	}
+}
+
+// testimonialReader is what read-only commands need from Contentful.
+type testimonialReader interface {
+	GetTestimonials(ctx context.Context) (*contentful.TestimonialsResult, error)
+}
+
+// newReadClient returns a Content Delivery API client when a CDA token is
+// configured, so read-only commands don't need the CMA token, and the CMA
+// client otherwise. The CDA only sees published content.
+func newReadClient(cfg *config.Config) testimonialReader {
+	if cfg.CDAToken == "" {
+		return newContentfulClient(cfg)
+	}
+	client := contentful.NewDeliveryClient(cfg.SpaceID, cfg.CDAToken)
+	client.Environment = cfg.Environment
+	client.Locale = cfg.Locale
+	client.Model = modelFlag
+	if cfg.ContentfulBaseURL != "" {
+		client.BaseURL = contentful.DeliveryBaseURLFor(cfg.ContentfulBaseURL)
+	}
+	if cfg.SectionID != "" {
+		client.SectionID = cfg.SectionID
+	}
+	logger.Debug("Reading published content through the Content Delivery API")
+	return client
+}
+
+// readOnlyOverrides is configOverrides for commands that only read from
+// Contentful, which may use a CDA token instead of the CMA token.
+func readOnlyOverrides() config.Overrides {
+	o := configOverrides()
+	o.ReadOnly = true
+	return o
+}
+
+// verifyAccess runs client.VerifyAccess, reporting a rejected token as a
+// config error.
+func verifyAccess(ctx context.Context, client *contentful.Client) error {
+	err := client.VerifyAccess(ctx)
+	if errors.Is(err, contentful.ErrInvalidToken) {
+		return withCode(codeConfig, fmt.Errorf("preflight: %w; check CONTENTFUL_CMA_TOKEN", err))
+	}
+	if err != nil {
+		return withCode(codeContentful, fmt.Errorf("preflight: %w", err))
+	}
+	return nil
+}
+
+// newContentfulClient returns a client for the configured space,
+// environment and locale that reads and writes testimonials using the
+// --model content model.
+func newContentfulClient(cfg *config.Config) *contentful.Client {
+	client := contentful.NewClient(cfg.SpaceID, cfg.CMAToken)
+	client.Environment = cfg.Environment
+	client.Locale = cfg.Locale
+	if cfg.ContentfulBaseURL != "" {
+		client.BaseURL = cfg.ContentfulBaseURL
+	}
+	client.UploadBaseURL = cfg.ContentfulUploadBaseURL
+	if cfg.SectionID != "" {
+		client.SectionID = cfg.SectionID
+	}
+	if cfg.SectionTitle != "" {
+		client.SectionTitle = cfg.SectionTitle
+	}
+	client.Model = modelFlag
+	client.MaxRetries = cmaRetriesFlag
+	client.Tags = tagsFlag
+	client.Logger = logger
+	return client
 }
 
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		if errorCode(err) == codeNoChange {
+			os.Exit(exitCodeFor(err))
+		}
+		// The run summary already reports a partial run as JSON.
+		if errorCode(err) == codePartial && outputFlag == "json" {
+			os.Exit(exitCodeFor(err))
+		}
+		if outputFlag == "json" {
+			writeErrorJSON(os.Stdout, err)
+		} else {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(exitCodeFor(err))
 	}
 }
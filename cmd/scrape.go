@@ -1,218 +1,784 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"io"
 	"os"
+	"strings"
+	gosync "sync"
 	"time"
 
-	servicekit "github.com/alberto-moreno-sa/go-service-kit/contentful"
-	"github.com/alberto-moreno-sa/go-service-kit/gemini"
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/assets"
 	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/config"
 	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/contentful"
 	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/linkedin"
 	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/sync"
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/translate"
 	"github.com/spf13/cobra"
 )
 
-var profileFlag string
+var profileFlag []string
+var sectionIDFlag []string
 var translateFlag bool
 var forceFlag bool
+var translateConcurrencyFlag int
+var translateToFlag string
+var enrichConcurrencyFlag int
+var rateFlag float64
+var detectLanguageFlag bool
+var verifyAvatarsFlag bool
+var maxLinkedInRequestsFlag int
+var appendOnlyFlag bool
+var avatarSquareFlag bool
+var slugSeparatorFlag string
+var noEnrichFlag bool
+var dedupeQuotesFlag bool
+var noPublishFlag bool
+var updateExistingFlag bool
+var slugPreserveCaseFlag bool
+var profileConcurrencyFlag int
+var printURNsFlag bool
+var dedupeByFlag string
+var assetSinkFlag string
+var minRunIntervalFlag time.Duration
+var forceRunFlag bool
+var editsFileFlag string
+var silentSuccessFlag bool
+var minMutualsFlag int
+var strictFlag bool
+var fieldLimitsFlag map[string]int
+var retriesFlag int
+var retryDelayFlag time.Duration
+var exitCodeOnNoChangeFlag bool
+var voyagerBaseURLFlag string
+var restliProtocolVersionFlag string
+var bulkPublishAssetsFlag bool
+var scrapeTimeoutFlag time.Duration
+var translateTimeoutFlag time.Duration
+var writeTimeoutFlag time.Duration
+var nameFormatFlag string
+var avatarWidthFlag int
+var avatarMaxSizeFlag int
+var pruneFlag bool
+var skipPreflightFlag bool
+var batchTranslateFlag bool
+
+// scrapeStdout receives what scrape prints to stdout, so --silent-success
+// can buffer it along with the logs.
+var scrapeStdout io.Writer = os.Stdout
 
 var scrapeCmd = &cobra.Command{
 	Use:   "scrape",
 	Short: "Scrape LinkedIn recommendations and sync to Contentful",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		cfg, err := config.Load()
-		if err != nil {
-			return fmt.Errorf("config: %w", err)
+		run := func() error {
+			err := withRetries(retriesFlag, retryDelayFlag, runScrape)
+			if errors.Is(err, errNoChanges) || errorCode(err) == codePartial {
+				cmd.SilenceUsage = true
+			}
+			return err
+		}
+		if !silentSuccessFlag {
+			return run()
+		}
+		restore := bufferLogs()
+		err := run()
+		restore(err != nil && !errors.Is(err, errNoChanges))
+		return err
+	},
+}
+
+// runScrape scrapes every --profile and syncs each target section.
+func runScrape() error {
+	// Each stage derives its own timeout from this context.
+	ctx := context.Background()
+
+	cfg, err := config.Load(configOverrides())
+	if err != nil {
+		return withCode(codeConfig, fmt.Errorf("config: %w", err))
+	}
+	if voyagerBaseURLFlag != "" {
+		cfg.VoyagerBaseURL = voyagerBaseURLFlag
+	}
+	if restliProtocolVersionFlag != "" {
+		cfg.RestliProtocolVersion = restliProtocolVersionFlag
+	}
+	logger.Debug("Loaded config", "config", cfg.String())
+
+	if noEnrichFlag {
+		logger.Warn("--no-enrich is set; names, roles, companies and avatars will be missing")
+	}
+
+	switch assetSinkFlag {
+	case "contentful":
+	case "s3":
+		if cfg.S3.Bucket == "" || cfg.S3.AccessKeyID == "" || cfg.S3.SecretAccessKey == "" {
+			return withCode(codeConfig, fmt.Errorf("S3_BUCKET, S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY are required with --asset-sink=s3"))
 		}
+	default:
+		return withCode(codeUsage, fmt.Errorf("--asset-sink must be contentful or s3, got %q", assetSinkFlag))
+	}
+
+	if translateFlag && strings.TrimSpace(translateToFlag) == "" {
+		return withCode(codeUsage, fmt.Errorf("--translate-to must not be empty"))
+	}
+
+	if detectLanguageFlag && !translateFlag {
+		return withCode(codeUsage, fmt.Errorf("--detect-language requires --translate"))
+	}
+	if batchTranslateFlag && !translateFlag {
+		return withCode(codeUsage, fmt.Errorf("--batch-translate requires --translate"))
+	}
+
+	// A capped scrape can stop short of the full set, and pruning would then
+	// delete testimonials that are still on LinkedIn.
+	if pruneFlag && maxLinkedInRequestsFlag > 0 {
+		return withCode(codeUsage, fmt.Errorf("--prune cannot be combined with --max-linkedin-requests"))
+	}
 
-		if profileFlag == "" {
-			return fmt.Errorf("--profile flag is required")
+	if translateFlag {
+		if err := cfg.RequireGemini(); err != nil {
+			return withCode(codeConfig, fmt.Errorf("config: %w", err))
 		}
+	}
 
-		// Step 1: Scrape LinkedIn
-		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
-		defer cancel()
+	if err := validateDedupeBy(); err != nil {
+		return err
+	}
 
-		log.Println("Scraping LinkedIn recommendations...")
-		scraped, err := linkedin.Scrape(ctx, profileFlag, cfg.LinkedInCookie, verbose)
+	limits, err := fieldLimits()
+	if err != nil {
+		return withCode(codeUsage, err)
+	}
+
+	nameFormat, err := linkedin.ParseNameFormat(nameFormatFlag)
+	if err != nil {
+		return withCode(codeUsage, fmt.Errorf("--name-format: %w", err))
+	}
+
+	defaultSectionID := cfg.SectionID
+	if defaultSectionID == "" {
+		defaultSectionID = contentful.DefaultSectionID
+	}
+	targets, err := syncTargets(profileFlag, sectionIDFlag, defaultSectionID)
+	if err != nil {
+		return withCode(codeUsage, err)
+	}
+
+	var edits map[string]sync.Edit
+	if editsFileFlag != "" {
+		edits, err = sync.LoadEdits(editsFileFlag)
 		if err != nil {
-			return fmt.Errorf("scrape: %w", err)
+			return withCode(codeUsage, err)
 		}
-		log.Printf("Found %d recommendations\n", len(scraped))
+	}
 
-		if len(scraped) == 0 {
-			log.Println("No recommendations found. Selectors may need updating.")
-			return nil
+	if minRunIntervalFlag > 0 && !forceRunFlag {
+		if err := checkRunInterval(cfg, minRunIntervalFlag, sectionOrder(targets)); err != nil {
+			return withCode(codeUsage, err)
 		}
+	}
 
-		// Step 1.5: Translate quotes to English if requested
-		if translateFlag {
-			if cfg.GeminiAPIKey == "" {
-				return fmt.Errorf("GEMINI_API_KEY is required when using --translate")
+	if !skipPreflightFlag {
+		if err := preflightContentful(ctx, cfg, sectionOrder(targets)); err != nil {
+			return err
+		}
+	}
+
+	// Step 1: Scrape LinkedIn
+	scrapedByProfile, failedByProfile, err := scrapeProfiles(ctx, cfg, targets, nameFormat)
+	if err != nil {
+		return err
+	}
+
+	// Without enrichment there are no names to dedupe on, so stop here.
+	if noEnrichFlag {
+		for i, t := range targets {
+			logger.Info("Quotes scraped without enrichment", "profile", t.profile)
+			for j, rec := range scrapedByProfile[i] {
+				fmt.Fprintf(scrapeStdout, "%d. \"%s\"\n\n", j+1, rec.Quote)
 			}
-			log.Println("Translating quotes to English...")
-			for i := range scraped {
-				translated, err := gemini.Translate(ctx, cfg.GeminiAPIKey, scraped[i].Quote, "English")
-				if err != nil {
-					log.Printf("WARNING: translation failed for %s: %v", scraped[i].Name, err)
-					continue
-				}
-				log.Printf("Translated quote for %s", scraped[i].Name)
-				scraped[i].Quote = translated
+		}
+		logger.Info("No-enrich mode: skipping Contentful sync")
+		return nil
+	}
+
+	if edits != nil {
+		applyEdits(scrapedByProfile, edits)
+	}
+
+	filteredByProfile := make([]int, len(targets))
+	if minMutualsFlag > 0 {
+		for i, recs := range scrapedByProfile {
+			var dropped int
+			scrapedByProfile[i], dropped = sync.FilterMinMutuals(recs, minMutualsFlag)
+			filteredByProfile[i] = dropped
+			if dropped > 0 {
+				logger.Info("Skipped recommendations with too few mutual connections", "count", dropped, "profile", targets[i].profile, "min", minMutualsFlag)
 			}
 		}
+	}
 
-		// Step 2: Fetch existing testimonials from Contentful
-		cmaClient := contentful.NewClient(cfg.SpaceID, cfg.CMAToken)
-		result, err := cmaClient.GetTestimonials(ctx)
+	// Sync each section once, combining its profiles in flag order.
+	changed := false
+	var summary []*sync.Report
+	for _, sectionID := range sectionOrder(targets) {
+		var scraped []linkedin.Recommendation
+		var filtered, failed int
+		for i, t := range targets {
+			if t.sectionID == sectionID {
+				scraped = append(scraped, scrapedByProfile[i]...)
+				filtered += filteredByProfile[i]
+				failed += failedByProfile[i]
+			}
+		}
+		if len(targets) > 1 {
+			logger.Info("Syncing section", "section", sectionID)
+		}
+		report, err := syncSection(ctx, cfg, sectionID, scraped, filtered, failed, limits)
 		if err != nil {
-			return fmt.Errorf("contentful fetch: %w", err)
-		}
-		log.Printf("Existing testimonials: %d\n", len(result.Testimonials))
-
-		// Step 3: Merge (or replace if --force)
-		var merged []contentful.Testimonial
-		var newIndices []int
-
-		if forceFlag {
-			log.Println("Force mode: replacing all testimonials")
-			for i, rec := range scraped {
-				newIndices = append(newIndices, i)
-				merged = append(merged, contentful.Testimonial{
-					Name:        rec.Name,
-					Role:        rec.Role,
-					Company:     rec.Company,
-					Quote:       rec.Quote,
-					AvatarURL:   rec.AvatarURL,
-					LinkedInURL: rec.LinkedInURL,
-				})
+			if len(targets) > 1 {
+				return fmt.Errorf("section %s: %w", sectionID, err)
 			}
-		} else {
-			merged, newIndices = sync.Merge(result.Testimonials, scraped)
-			if len(newIndices) == 0 {
-				log.Println("No new recommendations to add. Everything is up to date.")
-				return nil
+			return err
+		}
+		logger.Info("Reconciliation", "section", sectionID, "summary", report.String())
+		summary = append(summary, report)
+		changed = changed || report.Written > 0
+	}
+	if outputFlag == "json" {
+		if err := writeSummaryJSON(os.Stdout, summary); err != nil {
+			return withCode(codeInternal, fmt.Errorf("write summary: %w", err))
+		}
+	}
+	if err := partialFailure(summary); err != nil {
+		return err
+	}
+	if !changed && exitCodeOnNoChangeFlag {
+		return errNoChanges
+	}
+	return nil
+}
+
+// partialFailure returns a codePartial error summarizing the items that
+// failed across sections, or nil when none did. What succeeded has already
+// been written and published.
+func partialFailure(summary []*sync.Report) error {
+	var avatarsFailed, avatarsAttempted, translationsFailed int
+	for _, r := range summary {
+		avatarsFailed += r.AvatarsFailed
+		avatarsAttempted += r.AvatarsAttempted
+		translationsFailed += r.TranslationsFailed
+	}
+	var parts []string
+	if avatarsFailed > 0 {
+		parts = append(parts, fmt.Sprintf("%d of %d avatars failed to upload", avatarsFailed, avatarsAttempted))
+	}
+	if translationsFailed > 0 {
+		parts = append(parts, fmt.Sprintf("%d quotes could not be translated", translationsFailed))
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+	return withCode(codePartial, fmt.Errorf("synced with failures: %s", strings.Join(parts, "; ")))
+}
+
+// bufferLogs redirects log output and scrapeStdout into a buffer for
+// --silent-success. The returned func restores them and replays the
+// buffered lines only when the run failed, so errors keep their context.
+func bufferLogs() func(failed bool) {
+	var buf bytes.Buffer
+	prev := logOutput.swap(&buf)
+	scrapeStdout = &buf
+	return func(failed bool) {
+		logOutput.swap(prev)
+		scrapeStdout = os.Stdout
+		if failed {
+			fmt.Fprint(os.Stderr, buf.String())
+		}
+	}
+}
+
+// validateDedupeBy checks the --dedupe-by flag shared by scrape and diff.
+func validateDedupeBy() error {
+	switch sync.DedupeStrategy(dedupeByFlag) {
+	case sync.DedupeNameCompany, sync.DedupeSlug:
+		return nil
+	default:
+		return withCode(codeUsage, fmt.Errorf("--dedupe-by must be name or slug, got %q", dedupeByFlag))
+	}
+}
+
+// serviceName identifies this tool's entries in the shared build log.
+const serviceName = "linkedin-contentful-sync"
+
+// syncTarget pairs a LinkedIn profile with the siteSection it syncs into.
+type syncTarget struct {
+	profile   string
+	sectionID string
+}
+
+// syncTargets pairs --profile values with --section-id values by position.
+// With no section IDs every profile uses defaultSectionID; a single
+// section ID is shared by all profiles.
+func syncTargets(profiles, sectionIDs []string, defaultSectionID string) ([]syncTarget, error) {
+	if len(profiles) == 0 {
+		return nil, fmt.Errorf("--profile flag is required")
+	}
+	if len(sectionIDs) > 1 && len(sectionIDs) != len(profiles) {
+		return nil, fmt.Errorf("got %d --section-id values for %d profiles; pass one per profile", len(sectionIDs), len(profiles))
+	}
+
+	targets := make([]syncTarget, len(profiles))
+	for i, p := range profiles {
+		targets[i] = syncTarget{profile: p, sectionID: defaultSectionID}
+		switch len(sectionIDs) {
+		case 0:
+		case 1:
+			targets[i].sectionID = sectionIDs[0]
+		default:
+			targets[i].sectionID = sectionIDs[i]
+		}
+	}
+	return targets, nil
+}
+
+// sectionOrder returns the distinct section IDs of targets in first-seen order.
+func sectionOrder(targets []syncTarget) []string {
+	seen := make(map[string]bool)
+	var order []string
+	for _, t := range targets {
+		if !seen[t.sectionID] {
+			seen[t.sectionID] = true
+			order = append(order, t.sectionID)
+		}
+	}
+	return order
+}
+
+// scrapeProfiles scrapes every target's profile, running at most
+// --profile-concurrency scrapes at once. Results and the failed lookup
+// counts are indexed like targets.
+func scrapeProfiles(ctx context.Context, cfg *config.Config, targets []syncTarget, nameFormat linkedin.NameFormat) ([][]linkedin.Recommendation, []int, error) {
+	results := make([][]linkedin.Recommendation, len(targets))
+	failed := make([]int, len(targets))
+	errs := make([]error, len(targets))
+
+	// One budget and rate for the whole run, however many profiles are
+	// scraped at once. Since profiles share the rate, the timeout covers
+	// them all rather than each one.
+	limiter := linkedin.NewLimiter(maxLinkedInRequestsFlag, rateFlag)
+	ctx, cancel := context.WithTimeout(ctx, scrapeTimeoutFlag)
+	defer cancel()
+	concurrency := max(profileConcurrencyFlag, 1)
+	sem := make(chan struct{}, concurrency)
+	var wg gosync.WaitGroup
+	for i, t := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			start := time.Now()
+			logger.Info("Scraping LinkedIn recommendations", "profile", t.profile)
+			results[i], failed[i], errs[i] = linkedin.Scrape(ctx, t.profile, cfg.LinkedInCookie, linkedin.Options{
+				Verbose:           verbose,
+				Limiter:           limiter,
+				EnrichConcurrency: enrichConcurrencyFlag,
+				NoEnrich:          noEnrichFlag,
+				PrintURNs:         printURNsFlag,
+				NameFormat:        nameFormat,
+				AvatarWidth:       avatarWidthFlag,
+				BaseURL:           cfg.VoyagerBaseURL,
+				ProtocolVersion:   cfg.RestliProtocolVersion,
+				Logger:            logger.With("profile", t.profile),
+			})
+			if errs[i] == nil {
+				logger.Info("Found recommendations", "count", len(results[i]), "profile", t.profile, "elapsed", time.Since(start).Round(time.Millisecond))
+				scrapedAt := start.UTC().Format(time.RFC3339)
+				for j := range results[i] {
+					results[i][j].SourceProfile = t.profile
+					results[i][j].ScrapedAt = scrapedAt
+					results[i][j].ToolVersion = Version
+				}
 			}
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			if len(targets) > 1 {
+				err = fmt.Errorf("profile %s: %w", targets[i].profile, err)
+			}
+			return nil, nil, scrapeError(err)
 		}
-		log.Printf("Syncing %d recommendations (new: %d)\n", len(merged), len(newIndices))
+	}
+	return results, failed, nil
+}
+
+// scrapeError assigns an exit code to a linkedin.Scrape failure. A
+// rejected session cookie is a config problem with one fix, so it gets a
+// single actionable message.
+func scrapeError(err error) error {
+	if errors.Is(err, linkedin.ErrCookieExpired) {
+		return withCode(codeConfig, fmt.Errorf("LinkedIn rejected the session cookie; refresh LINKEDIN_COOKIE with a new li_at value (%w)", err))
+	}
+	return withCode(codeLinkedIn, fmt.Errorf("scrape: %w", err))
+}
+
+// syncSection translates scraped recommendations if requested, syncs them
+// into one siteSection entry with sync.Run and records the run in the
+// build log. filtered is how many were dropped before the call, and
+// failedLookups how many recommender lookups failed while scraping.
+func syncSection(parent context.Context, cfg *config.Config, sectionID string, scraped []linkedin.Recommendation, filtered, failedLookups int, limits sync.FieldLimits) (*sync.Report, error) {
+	if len(scraped) == 0 {
+		logger.Warn("No recommendations found; LinkedIn's API may have changed")
+		return &sync.Report{SectionID: sectionID, Scraped: filtered, Filtered: filtered}, nil
+	}
 
-		// Step 3.5: Upload avatars for new recommendations
-		for _, idx := range newIndices {
-			t := &merged[idx]
-			if t.AvatarURL == "" {
+	// Step 1.5: Translate quotes to the --translate-to language if requested
+	var translationsFailed int
+	if translateFlag {
+		targetLang := translateToFlag
+		logger.Info("Translating quotes", "to", targetLang)
+		quotes := make([]string, len(scraped))
+		for i := range scraped {
+			quotes[i] = scraped[i].Quote
+		}
+		translateStart := time.Now()
+		translateCtx, cancelTranslate := context.WithTimeout(parent, translateTimeoutFlag)
+		var translated []*translate.TranslateResult
+		var errs []error
+		switch {
+		case batchTranslateFlag:
+			translated, errs = batchTranslate(translateCtx, cfg.GeminiAPIKey, quotes, targetLang)
+		case detectLanguageFlag:
+			translated, errs = translate.DetectAndTranslateAll(translateCtx, cfg.GeminiAPIKey, quotes, targetLang, translateConcurrencyFlag)
+		default:
+			translated, errs = translate.TranslateIfNeededAll(translateCtx, cfg.GeminiAPIKey, quotes, targetLang, translateConcurrencyFlag)
+		}
+		cancelTranslate()
+		var translatedCount, promptTokens, outputTokens int
+		for i := range scraped {
+			if errs[i] != nil {
+				logger.Warn("Translation failed", "name", scraped[i].Name, "err", errs[i])
+				translationsFailed++
 				continue
 			}
-			log.Printf("Uploading avatar for %s...", t.Name)
-			cdnURL, err := cmaClient.UploadAvatar(ctx, t.AvatarURL, t.Name)
-			if err != nil {
-				log.Printf("WARNING: avatar upload failed for %s: %v", t.Name, err)
-				t.AvatarURL = ""
+			promptTokens += translated[i].PromptTokens
+			outputTokens += translated[i].OutputTokens
+			if translate.SameLanguage(translated[i].SourceLang, targetLang) {
+				logger.Debug("Quote is already in the target language", "name", scraped[i].Name, "lang", targetLang)
+				scraped[i].QuoteLang = targetLang
 				continue
 			}
-			t.AvatarURL = cdnURL
-			log.Printf("Avatar uploaded for %s: ok", t.Name)
+			logger.Debug("Translated quote", "name", scraped[i].Name, "from", translated[i].SourceLang,
+				"model", translated[i].Model, "elapsed", translated[i].Elapsed.Round(time.Millisecond),
+				"tokens_in", translated[i].PromptTokens, "tokens_out", translated[i].OutputTokens)
+			scraped[i].Quote = translated[i].Text
+			scraped[i].QuoteLang = targetLang
+			translatedCount++
 		}
+		logger.Info("Translated quotes", "translated", translatedCount, "total", len(scraped),
+			"elapsed", time.Since(translateStart).Round(time.Millisecond),
+			"tokens_in", promptTokens, "tokens_out", outputTokens)
+	}
 
-		// Step 4: Create or Update + Publish
-		var entryID string
-		var newVersion int
+	// Step 2: Merge into Contentful, upload avatars, write and publish.
+	// Everything from here to the build log shares the write-stage timeout.
+	ctx, cancel := context.WithTimeout(parent, writeTimeoutFlag)
+	defer cancel()
+	cmaClient := newContentfulClient(cfg)
+	cmaClient.SectionID = sectionID
+	cmaClient.VerifyAvatars = verifyAvatarsFlag
+	cmaClient.SquareAvatars = avatarSquareFlag
+	cmaClient.MaxAvatarSize = avatarMaxSizeFlag
+	cmaClient.DeferAssetPublish = bulkPublishAssetsFlag || noPublishFlag
+	cmaClient.DraftReferences = noPublishFlag
+	cmaClient.Slug = assets.SlugOptions{
+		Separator:    slugSeparatorFlag,
+		PreserveCase: slugPreserveCaseFlag,
+	}
+	if assetSinkFlag == "s3" {
+		s3 := assets.NewS3Uploader(cfg.S3)
+		s3.Slug = cmaClient.Slug
+		cmaClient.AvatarSink = s3
+	}
 
-		if result.EntryID == "" {
-			// Entry doesn't exist yet — create it
-			log.Println("Creating new testimonials entry in Contentful...")
-			entryID, newVersion, err = cmaClient.CreateTestimonials(ctx, merged)
-			if err != nil {
-				return fmt.Errorf("contentful create: %w", err)
-			}
-		} else {
-			// Entry exists — update it
-			entryID = result.EntryID
-			newVersion, err = cmaClient.UpdateTestimonials(ctx, result, merged)
-			if err != nil {
-				return fmt.Errorf("contentful update: %w", err)
-			}
+	report, err := sync.Run(ctx, sync.Deps{Store: cmaClient, Logger: logger}, sync.RunOptions{
+		SectionID:       sectionID,
+		Recommendations: scraped,
+		Filtered:        filtered,
+		LookupFailures:  failedLookups,
+		Force:           forceFlag,
+		Merge: sync.MergeOptions{
+			Strategy:       sync.DedupeStrategy(dedupeByFlag),
+			AppendOnly:     appendOnlyFlag,
+			UpdateExisting: updateExistingFlag,
+			DedupeQuotes:   dedupeQuotesFlag,
+		},
+		Prune:           pruneFlag,
+		Limits:          limits,
+		Strict:          strictFlag,
+		LastContentHash: lastContentHash(ctx, cmaClient),
+		NoPublish:       noPublishFlag,
+	})
+	report.TranslationsFailed = translationsFailed
+	if err != nil {
+		if errors.Is(err, sync.ErrFieldTooLong) {
+			return report, withCode(codeValidation, err)
 		}
+		return report, withCode(codeContentful, err)
+	}
+	if report.Written == 0 {
+		return report, nil
+	}
 
-		err = cmaClient.PublishEntry(ctx, entryID, newVersion)
+	// Step 3: Record build log
+	recordBuildLog(ctx, cmaClient, report)
+	return report, nil
+}
+
+// batchTranslate runs translate.TranslateBatch and reports its results the
+// way the per-quote translators do. A quote that comes back unchanged is
+// taken to be in targetLang already; if the batch fails, every quote fails.
+func batchTranslate(ctx context.Context, apiKey string, quotes []string, targetLang string) ([]*translate.TranslateResult, []error) {
+	results := make([]*translate.TranslateResult, len(quotes))
+	errs := make([]error, len(quotes))
+	start := time.Now()
+	texts, err := translate.TranslateBatch(ctx, apiKey, quotes, targetLang)
+	for i := range quotes {
 		if err != nil {
-			return fmt.Errorf("contentful publish: %w", err)
+			errs[i] = err
+			continue
 		}
+		results[i] = &translate.TranslateResult{Text: texts[i], Model: translate.Model, Elapsed: time.Since(start)}
+		if texts[i] == strings.TrimSpace(quotes[i]) {
+			results[i].SourceLang = targetLang
+		}
+	}
+	return results, errs
+}
 
-		log.Println("Successfully synced and published.")
+// recordBuildLog appends this run to the shared build log and publishes
+// it. Failures are logged; the sync itself already succeeded.
+func recordBuildLog(ctx context.Context, cmaClient *contentful.Client, report *sync.Report) {
+	logger.Debug("Recording build log")
+	triggeredBy := "local"
+	if os.Getenv("GITHUB_ACTIONS") == "true" {
+		triggeredBy = "github-actions"
+	}
+	status := "success"
+	if !report.Published {
+		status = "draft"
+	} else if report.Failures() > 0 {
+		status = "partial"
+	}
 
-		// Step 5: Record build log
-		log.Println("Recording build log...")
-		const serviceName = "linkedin-contentful-sync"
-		triggeredBy := "local"
-		if os.Getenv("GITHUB_ACTIONS") == "true" {
-			triggeredBy = "github-actions"
-		}
+	logEntry := contentful.BuildLogEntry{
+		Service:         serviceName,
+		SectionID:       cmaClient.SectionID,
+		Timestamp:       time.Now().UTC().Format(time.RFC3339),
+		TriggeredBy:     triggeredBy,
+		ForceUpdate:     forceFlag,
+		TranslationUsed: translateFlag,
+		NewAdded:        report.New,
+		TotalAfterSync:  report.Written,
+		Status:          status,
+		ContentHash:     report.ContentHash,
+	}
 
-		logEntry := servicekit.BuildLogEntry{
-			Service:         serviceName,
-			Timestamp:       time.Now().UTC().Format(time.RFC3339),
-			TriggeredBy:     triggeredBy,
-			ForceUpdate:     forceFlag,
-			TranslationUsed: translateFlag,
-			NewAdded:        len(newIndices),
-			TotalAfterSync:  len(merged),
-			Status:          "success",
-		}
+	buildLogResult, err := cmaClient.GetBuildLog(ctx)
+	if err != nil {
+		logger.Warn("Failed to fetch build log", "err", err)
+		return
+	}
+
+	allLogEntries := trimBuildLog(append(buildLogResult.Entries, logEntry), serviceName, 3)
 
-		buildLogResult, err := cmaClient.GetBuildLog(ctx)
+	var buildLogEntryID string
+	var buildLogVersion int
+
+	if buildLogResult.EntryID == "" {
+		buildLogEntryID, buildLogVersion, err = cmaClient.CreateBuildLog(ctx, allLogEntries)
 		if err != nil {
-			log.Printf("WARNING: failed to fetch build log: %v", err)
-			return nil
+			logger.Warn("Failed to create build log", "err", err)
+			return
 		}
-
-		var ownEntries, otherEntries []servicekit.BuildLogEntry
-		for _, e := range buildLogResult.Entries {
-			if e.Service == serviceName {
-				ownEntries = append(ownEntries, e)
-			} else {
-				otherEntries = append(otherEntries, e)
-			}
+	} else {
+		buildLogEntryID = buildLogResult.EntryID
+		buildLogVersion, err = cmaClient.UpdateBuildLog(ctx, buildLogResult, allLogEntries)
+		if err != nil {
+			logger.Warn("Failed to update build log", "err", err)
+			return
 		}
-		if len(ownEntries) >= 3 {
-			ownEntries = ownEntries[len(ownEntries)-2:]
+	}
+
+	if err := cmaClient.PublishEntry(ctx, buildLogEntryID, buildLogVersion); err != nil {
+		logger.Warn("Failed to publish build log", "err", err)
+		return
+	}
+
+	logger.Info("Build log updated", "entries", len(allLogEntries))
+}
+
+func init() {
+	scrapeCmd.Flags().StringSliceVar(&profileFlag, "profile", nil, "LinkedIn username (e.g. alberthiggs); repeat to sync several profiles")
+	scrapeCmd.Flags().IntVar(&profileConcurrencyFlag, "profile-concurrency", 2, "Number of profiles to scrape in parallel")
+	scrapeCmd.Flags().StringSliceVar(&sectionIDFlag, "section-id", nil, "siteSection sectionId to sync into; repeat to pair with each --profile")
+	scrapeCmd.Flags().BoolVar(&translateFlag, "translate", false, "Translate quotes using Gemini (see --translate-to)")
+	scrapeCmd.Flags().StringVar(&translateToFlag, "translate-to", translate.DefaultTargetLang, "Language to translate quotes to, e.g. Spanish or German")
+	scrapeCmd.Flags().BoolVar(&detectLanguageFlag, "detect-language", false, "With --translate, detect the language and translate in one Gemini call per quote instead of two")
+	scrapeCmd.Flags().Float64Var(&rateFlag, "rate", 1, "Maximum LinkedIn requests per second across all profiles (0 for no limit)")
+	scrapeCmd.Flags().IntVar(&enrichConcurrencyFlag, "enrich-concurrency", 4, "Number of recommenders whose profile and company are looked up in parallel")
+	scrapeCmd.Flags().BoolVar(&batchTranslateFlag, "batch-translate", false, "With --translate, send all of a section's quotes to Gemini in one call instead of one call per quote")
+	scrapeCmd.Flags().IntVar(&translateConcurrencyFlag, "translate-concurrency", 4, "Number of quotes to translate in parallel")
+	scrapeCmd.Flags().BoolVar(&forceFlag, "force", false, "Replace testimonials synced from LinkedIn instead of merging; ones without a LinkedIn URL are kept")
+	scrapeCmd.Flags().BoolVar(&verifyAvatarsFlag, "verify-avatars", false, "Wait until uploaded avatars are fetchable from the CDN")
+	scrapeCmd.Flags().StringVar(&assetSinkFlag, "asset-sink", "contentful", "Where to store avatars: contentful or s3")
+	scrapeCmd.Flags().IntVar(&avatarWidthFlag, "avatar-width", linkedin.DefaultAvatarWidth, "Smallest avatar width to download in pixels; the widest available is used if none is this wide")
+	scrapeCmd.Flags().IntVar(&avatarMaxSizeFlag, "avatar-max-size", 0, "Shrink avatars larger than this many pixels on either side and re-encode them as JPEG (0 keeps originals)")
+	scrapeCmd.Flags().BoolVar(&avatarSquareFlag, "avatar-square", false, "Center-crop avatars to a square before uploading")
+	scrapeCmd.Flags().StringVar(&slugSeparatorFlag, "slug-separator", "-", "Separator used in avatar file names")
+	scrapeCmd.Flags().BoolVar(&slugPreserveCaseFlag, "slug-preserve-case", false, "Keep name casing in avatar file names")
+	scrapeCmd.Flags().BoolVar(&noEnrichFlag, "no-enrich", false, "Skip recommender profile/company lookups and only report quotes (no sync)")
+	scrapeCmd.Flags().BoolVar(&printURNsFlag, "print-urns", false, "Log the profile URNs and LinkedIn endpoints used")
+	scrapeCmd.Flags().IntVar(&maxLinkedInRequestsFlag, "max-linkedin-requests", 0, "Maximum LinkedIn API requests per run, shared by all profiles (0 = unlimited)")
+	scrapeCmd.Flags().BoolVar(&appendOnlyFlag, "append-only", false, "Only append new testimonials; never modify, remove, or reorder existing ones")
+	scrapeCmd.Flags().BoolVar(&skipPreflightFlag, "skip-preflight", false, "Don't check the CMA token and write access before scraping")
+	scrapeCmd.Flags().BoolVar(&pruneFlag, "prune", false, "Remove stored testimonials with a LinkedIn URL that are no longer among the scraped recommendations")
+	scrapeCmd.Flags().StringVar(&dedupeByFlag, "dedupe-by", string(sync.DedupeNameCompany), "Dedupe key: name (name + company) or slug (LinkedIn profile slug, falling back to name + company when a URL is missing)")
+	scrapeCmd.Flags().BoolVar(&updateExistingFlag, "update-existing", true, "Refresh the quote, role and company of stored testimonials that changed on LinkedIn (set false to only add new ones)")
+	scrapeCmd.Flags().BoolVar(&noPublishFlag, "no-publish", false, "Write the entry and avatars as drafts for review instead of publishing them")
+	scrapeCmd.Flags().BoolVar(&dedupeQuotesFlag, "dedupe-quotes", false, "Also skip recommendations whose quote text matches an existing one")
+	scrapeCmd.Flags().StringVar(&editsFileFlag, "edits-file", "", "JSON file of manual quote overrides keyed by profile slug or name")
+	scrapeCmd.Flags().BoolVar(&silentSuccessFlag, "silent-success", false, "Print nothing when the run succeeds; on failure, replay the log before the error")
+	scrapeCmd.Flags().IntVar(&minMutualsFlag, "min-mutuals", 0, "Skip recommenders with fewer mutual connections (unknown counts are kept)")
+	scrapeCmd.Flags().BoolVar(&strictFlag, "strict", false, "Fail instead of truncating fields longer than their limit")
+	scrapeCmd.Flags().StringToIntVar(&fieldLimitsFlag, "field-limits", nil, "Per-field length limits, e.g. role=120,company=80 (name, role, company, quote)")
+	scrapeCmd.Flags().IntVar(&retriesFlag, "retries", 0, "Retry the whole run this many times on network, rate-limit or 5xx errors")
+	scrapeCmd.Flags().DurationVar(&retryDelayFlag, "retry-delay", 30*time.Second, "Wait between --retries attempts")
+	scrapeCmd.Flags().BoolVar(&exitCodeOnNoChangeFlag, "exit-code-on-nochange", false, "Exit with status 78 instead of 0 when nothing was written")
+	scrapeCmd.Flags().StringVar(&voyagerBaseURLFlag, "voyager-base-url", "", "Voyager API base URL (overrides LINKEDIN_VOYAGER_BASE_URL; default "+linkedin.DefaultVoyagerBaseURL+")")
+	scrapeCmd.Flags().StringVar(&restliProtocolVersionFlag, "restli-protocol-version", "", "x-restli-protocol-version header (overrides LINKEDIN_RESTLI_PROTOCOL_VERSION; default "+linkedin.DefaultProtocolVersion+")")
+	scrapeCmd.Flags().BoolVar(&bulkPublishAssetsFlag, "bulk-publish-assets", false, "Publish all new avatar assets in one bulk action after uploading")
+	scrapeCmd.Flags().DurationVar(&scrapeTimeoutFlag, "scrape-timeout", 5*time.Minute, "Time limit for scraping all LinkedIn profiles")
+	scrapeCmd.Flags().DurationVar(&translateTimeoutFlag, "translate-timeout", 30*time.Second, "Time limit for translating a section's quotes")
+	scrapeCmd.Flags().DurationVar(&writeTimeoutFlag, "write-timeout", 2*time.Minute, "Time limit for a section's Contentful reads, avatar uploads and writes")
+	scrapeCmd.Flags().StringVar(&nameFormatFlag, "name-format", string(linkedin.NameFull), "How to store recommender names: full, first or last-first")
+	scrapeCmd.MarkFlagsMutuallyExclusive("append-only", "force")
+	scrapeCmd.MarkFlagsMutuallyExclusive("batch-translate", "detect-language")
+	scrapeCmd.MarkFlagsMutuallyExclusive("prune", "append-only")
+	scrapeCmd.MarkFlagsMutuallyExclusive("prune", "force")
+	scrapeCmd.MarkFlagsMutuallyExclusive("bulk-publish-assets", "verify-avatars")
+	scrapeCmd.MarkFlagsMutuallyExclusive("no-publish", "verify-avatars")
+	scrapeCmd.Flags().DurationVar(&minRunIntervalFlag, "min-run-interval", 0, "Refuse to run if the last successful run was more recent than this (e.g. 6h)")
+	scrapeCmd.Flags().BoolVar(&forceRunFlag, "force-run", false, "Run even if --min-run-interval has not elapsed")
+	rootCmd.AddCommand(scrapeCmd)
+}
+
+// fieldLimits returns the default field limits with --field-limits applied.
+func fieldLimits() (sync.FieldLimits, error) {
+	limits := sync.DefaultFieldLimits
+	for field, limit := range fieldLimitsFlag {
+		if err := limits.Set(field, limit); err != nil {
+			return limits, fmt.Errorf("--field-limits: %w", err)
 		}
-		allLogEntries := append(otherEntries, append(ownEntries, logEntry)...)
+	}
+	return limits, nil
+}
 
-		var buildLogEntryID string
-		var buildLogVersion int
+// preflightContentful checks the token can write every target section
+// before any time is spent scraping.
+func preflightContentful(ctx context.Context, cfg *config.Config, sectionIDs []string) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
 
-		if buildLogResult.EntryID == "" {
-			buildLogEntryID, buildLogVersion, err = cmaClient.CreateBuildLog(ctx, allLogEntries)
-			if err != nil {
-				log.Printf("WARNING: failed to create build log: %v", err)
-				return nil
-			}
-		} else {
-			buildLogEntryID = buildLogResult.EntryID
-			buildLogVersion, err = cmaClient.UpdateBuildLog(ctx, buildLogResult, allLogEntries)
-			if err != nil {
-				log.Printf("WARNING: failed to update build log: %v", err)
-				return nil
-			}
+	client := newContentfulClient(cfg)
+	if err := verifyAccess(ctx, client); err != nil {
+		return err
+	}
+	for _, sectionID := range sectionIDs {
+		client.SectionID = sectionID
+		if err := client.CheckWriteAccess(ctx); err != nil {
+			return withCode(codeContentful, fmt.Errorf("preflight for section %s: %w", sectionID, err))
 		}
+	}
+	return nil
+}
 
-		if err := cmaClient.PublishEntry(ctx, buildLogEntryID, buildLogVersion); err != nil {
-			log.Printf("WARNING: failed to publish build log: %v", err)
-			return nil
+// applyEdits overrides scraped quotes from the edits file and warns about
+// edits whose source quote has changed since they were written.
+func applyEdits(scrapedByProfile [][]linkedin.Recommendation, edits map[string]sync.Edit) {
+	total := 0
+	for _, recs := range scrapedByProfile {
+		applied, stale := sync.ApplyEdits(recs, edits)
+		total += applied
+		for _, s := range stale {
+			logger.Warn("Edit may be stale; set originalHash to silence", "key", s.Key, "hash", s.Hash)
 		}
+	}
+	logger.Info("Applied quote edits", "applied", total, "edits", len(edits))
+}
 
-		log.Printf("Build log updated (%d total entries)", len(allLogEntries))
+// checkRunInterval refuses to run when this tool's last successful build-log
+// entry for any of sectionIDs is more recent than interval ago.
+func checkRunInterval(cfg *config.Config, interval time.Duration, sectionIDs []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client := newContentfulClient(cfg)
+	buildLog, err := client.GetBuildLog(ctx)
+	if err != nil {
+		logger.Warn("Could not read build log for --min-run-interval", "err", err)
 		return nil
-	},
+	}
+
+	for _, sectionID := range sectionIDs {
+		for i := len(buildLog.Entries) - 1; i >= 0; i-- {
+			e := buildLog.Entries[i]
+			if e.Service != serviceName || e.SectionID != sectionID || (e.Status != "success" && e.Status != "partial") {
+				continue
+			}
+			last, err := time.Parse(time.RFC3339, e.Timestamp)
+			if err != nil {
+				break
+			}
+			next := last.Add(interval)
+			if time.Now().Before(next) {
+				return fmt.Errorf("last successful run for section %s was at %s; next run allowed after %s (use --force-run to override)",
+					sectionID, last.Local().Format(time.RFC3339), next.Local().Format(time.RFC3339))
+			}
+			break
+		}
+	}
+	return nil
 }
 
-func init() {
-	scrapeCmd.Flags().StringVar(&profileFlag, "profile", "", "LinkedIn username (e.g. alberthiggs)")
-	scrapeCmd.Flags().BoolVar(&translateFlag, "translate", false, "Translate quotes to English using Gemini")
-	scrapeCmd.Flags().BoolVar(&forceFlag, "force", false, "Replace all existing testimonials instead of merging")
-	rootCmd.AddCommand(scrapeCmd)
+// lastContentHash returns the content hash recorded by this tool's most recent
+// build-log entry for client's section, or "" if there is none or the log
+// can't be read. Entries without a section ID predate multi-section syncs
+// and are never matched.
+func lastContentHash(ctx context.Context, client *contentful.Client) string {
+	buildLog, err := client.GetBuildLog(ctx)
+	if err != nil {
+		logger.Warn("Failed to fetch build log", "err", err)
+		return ""
+	}
+	for i := len(buildLog.Entries) - 1; i >= 0; i-- {
+		if buildLog.Entries[i].Service == serviceName && buildLog.Entries[i].SectionID == client.SectionID {
+			// A draft run never published its content, so it can't be
+			// used to skip this one.
+			if buildLog.Entries[i].Status == "draft" {
+				return ""
+			}
+			return buildLog.Entries[i].ContentHash
+		}
+	}
+	return ""
 }
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/config"
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/sync"
+	"github.com/spf13/cobra"
+)
+
+var statsJSONFlag bool
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Summarize the testimonials in Contentful",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadContentful(readOnlyOverrides())
+		if err != nil {
+			return withCode(codeConfig, fmt.Errorf("config: %w", err))
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		result, err := newReadClient(cfg).GetTestimonials(ctx)
+		if err != nil {
+			return withCode(codeContentful, fmt.Errorf("fetch: %w", err))
+		}
+		stats := sync.ComputeStats(result.Testimonials)
+
+		if statsJSONFlag {
+			data, err := json.MarshalIndent(stats, "", "  ")
+			if err != nil {
+				return withCode(codeInternal, fmt.Errorf("write JSON: %w", err))
+			}
+			if _, err := fmt.Fprintln(os.Stdout, string(data)); err != nil {
+				return withCode(codeInternal, fmt.Errorf("write JSON: %w", err))
+			}
+			return nil
+		}
+
+		if stats.Total == 0 {
+			fmt.Println("No testimonials found.")
+			return nil
+		}
+		fmt.Printf("Testimonials:       %d\n", stats.Total)
+		fmt.Printf("With avatar:        %d\n", stats.WithAvatar)
+		fmt.Printf("With LinkedIn URL:  %d\n", stats.WithLinkedInURL)
+		fmt.Printf("Distinct companies: %d\n", stats.Companies)
+		fmt.Printf("Average quote:      %.0f characters\n", stats.AvgQuoteLength)
+		fmt.Printf("Longest quote:      %d characters (%s)\n", stats.LongestQuoteLength, stats.LongestQuoteName)
+		return nil
+	},
+}
+
+func init() {
+	statsCmd.Flags().BoolVar(&statsJSONFlag, "json", false, "Print the stats as a JSON object")
+	rootCmd.AddCommand(statsCmd)
+}
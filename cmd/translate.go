@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/config"
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/translate"
+	"github.com/spf13/cobra"
+)
+
+var translateCmdToFlag string
+var translateCmdFileFlag string
+var translateCmdJSONFlag bool
+var translateCmdDetectFlag bool
+var translateCmdConcurrencyFlag int
+var translateCmdTimeoutFlag time.Duration
+
+var translateCmd = &cobra.Command{
+	Use:   "translate [text...]",
+	Short: "Translate quotes with Gemini without syncing anything",
+	Long: "Translates each argument, or every quote in --file, with the same Gemini model and prompts scrape uses. " +
+		"Handy for checking GEMINI_API_KEY or a translation before a sync.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if strings.TrimSpace(translateCmdToFlag) == "" {
+			return withCode(codeUsage, fmt.Errorf("--to must not be empty"))
+		}
+		texts := args
+		if translateCmdFileFlag != "" {
+			quotes, err := readQuotesFile(translateCmdFileFlag)
+			if err != nil {
+				return withCode(codeUsage, err)
+			}
+			texts = append(texts, quotes...)
+		}
+		if len(texts) == 0 {
+			return withCode(codeUsage, fmt.Errorf("pass text to translate or --file"))
+		}
+
+		cfg, err := config.LoadGemini(configOverrides())
+		if err != nil {
+			return withCode(codeConfig, fmt.Errorf("config: %w", err))
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), translateCmdTimeoutFlag)
+		defer cancel()
+
+		var results []*translate.TranslateResult
+		var errs []error
+		if translateCmdDetectFlag {
+			results, errs = translate.DetectAndTranslateAll(ctx, cfg.GeminiAPIKey, texts, translateCmdToFlag, translateCmdConcurrencyFlag)
+		} else {
+			results, errs = translate.TranslateIfNeededAll(ctx, cfg.GeminiAPIKey, texts, translateCmdToFlag, translateCmdConcurrencyFlag)
+		}
+
+		type translation struct {
+			Text        string `json:"text"`
+			Translation string `json:"translation,omitempty"`
+			SourceLang  string `json:"sourceLang,omitempty"`
+			Error       string `json:"error,omitempty"`
+		}
+		out := make([]translation, len(texts))
+		var failed int
+		for i, text := range texts {
+			out[i].Text = text
+			if errs[i] != nil {
+				out[i].Error = errs[i].Error()
+				failed++
+				continue
+			}
+			out[i].Translation = results[i].Text
+			out[i].SourceLang = results[i].SourceLang
+			logger.Debug("Translated", "index", i+1, "from", results[i].SourceLang, "model", results[i].Model,
+				"elapsed", results[i].Elapsed.Round(time.Millisecond),
+				"tokens_in", results[i].PromptTokens, "tokens_out", results[i].OutputTokens)
+		}
+
+		if translateCmdJSONFlag {
+			data, err := json.MarshalIndent(out, "", "  ")
+			if err != nil {
+				return withCode(codeInternal, fmt.Errorf("write JSON: %w", err))
+			}
+			if _, err := fmt.Fprintln(os.Stdout, string(data)); err != nil {
+				return withCode(codeInternal, fmt.Errorf("write JSON: %w", err))
+			}
+		} else {
+			for i, t := range out {
+				if len(out) > 1 {
+					fmt.Printf("%d. ", i+1)
+				}
+				switch {
+				case t.Error != "":
+					fmt.Printf("(failed: %s)\n", t.Error)
+				case t.SourceLang != "":
+					fmt.Printf("[%s] %s\n", t.SourceLang, t.Translation)
+				default:
+					fmt.Println(t.Translation)
+				}
+			}
+		}
+
+		if failed > 0 {
+			return withCode(codeTranslate, fmt.Errorf("%d of %d translations failed", failed, len(texts)))
+		}
+		return nil
+	},
+}
+
+// readQuotesFile reads a JSON array of quote strings, or of testimonials
+// such as list --file writes, in which case their quotes are used.
+func readQuotesFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var quotes []string
+	if err := json.Unmarshal(data, &quotes); err == nil {
+		return quotes, nil
+	}
+	testimonials, err := readTestimonialsFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s is neither a JSON array of strings nor of testimonials: %w", path, err)
+	}
+	for _, t := range testimonials {
+		quotes = append(quotes, t.Quote)
+	}
+	return quotes, nil
+}
+
+func init() {
+	translateCmd.Flags().StringVar(&translateCmdToFlag, "to", translate.DefaultTargetLang, "Language to translate to, e.g. Spanish or German")
+	translateCmd.Flags().StringVar(&translateCmdFileFlag, "file", "", "JSON file with an array of quotes, or of testimonials as written by list --file")
+	translateCmd.Flags().BoolVar(&translateCmdJSONFlag, "json", false, "Print the results as a JSON array")
+	translateCmd.Flags().BoolVar(&translateCmdDetectFlag, "detect-language", false, "Detect the language and translate in one Gemini call per quote instead of two")
+	translateCmd.Flags().IntVar(&translateCmdConcurrencyFlag, "concurrency", 4, "Number of quotes to translate in parallel")
+	translateCmd.Flags().DurationVar(&translateCmdTimeoutFlag, "timeout", 2*time.Minute, "Time limit for all translations")
+	rootCmd.AddCommand(translateCmd)
+}
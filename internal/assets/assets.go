@@ -0,0 +1,109 @@
+package assets
+
+import (
+	"context"
+	"mime"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// AssetUploader stores an image and returns the URL it is served from.
+type AssetUploader interface {
+	Upload(ctx context.Context, data []byte, contentType, name string) (url string, err error)
+}
+
+// SlugOptions controls how names are turned into file names.
+type SlugOptions struct {
+	// Separator replaces spaces. Defaults to "-" when empty.
+	Separator string
+	// PreserveCase keeps uppercase letters instead of lowercasing.
+	PreserveCase bool
+}
+
+// Slugify turns a display name into a file-name-safe slug.
+func Slugify(name string, opts SlugOptions) string {
+	sep := opts.Separator
+	if sep == "" {
+		sep = "-"
+	}
+
+	s := strings.TrimSpace(name)
+	if !opts.PreserveCase {
+		s = strings.ToLower(s)
+	}
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == ' ':
+			b.WriteString(sep)
+		case (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '_':
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// imageExts maps the image content types avatars may be served as to
+// file extensions.
+var imageExts = map[string]string{
+	"image/jpeg":     ".jpg",
+	"image/jpg":      ".jpg",
+	"image/pjpeg":    ".jpg",
+	"image/png":      ".png",
+	"image/webp":     ".webp",
+	"image/gif":      ".gif",
+	"image/avif":     ".avif",
+	"image/svg+xml":  ".svg",
+	"image/bmp":      ".bmp",
+	"image/x-ms-bmp": ".bmp",
+	"image/tiff":     ".tiff",
+}
+
+// extContentTypes maps file extensions back to image content types.
+var extContentTypes = map[string]string{
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".webp": "image/webp",
+	".gif":  "image/gif",
+	".avif": "image/avif",
+	".svg":  "image/svg+xml",
+	".bmp":  "image/bmp",
+	".tif":  "image/tiff",
+	".tiff": "image/tiff",
+}
+
+// mediaType returns ct lowercased and without parameters such as charset.
+func mediaType(ct string) string {
+	if mt, _, err := mime.ParseMediaType(ct); err == nil {
+		return mt
+	}
+	mt, _, _ := strings.Cut(ct, ";")
+	return strings.ToLower(strings.TrimSpace(mt))
+}
+
+// ExtForContentType returns the file extension for an image content type,
+// or ".jpg" when the type isn't recognized.
+func ExtForContentType(ct string) string {
+	if ext, ok := imageExts[mediaType(ct)]; ok {
+		return ext
+	}
+	return ".jpg"
+}
+
+// ContentTypeFor returns ct when it is a recognized image type. Otherwise,
+// for missing or generic types like application/octet-stream, it returns
+// the type implied by the extension of sourceURL's path, or ct unchanged
+// when that isn't recognized either.
+func ContentTypeFor(ct, sourceURL string) string {
+	if _, ok := imageExts[mediaType(ct)]; ok {
+		return ct
+	}
+	if u, err := url.Parse(sourceURL); err == nil {
+		if t, ok := extContentTypes[strings.ToLower(path.Ext(u.Path))]; ok {
+			return t
+		}
+	}
+	return ct
+}
@@ -0,0 +1,110 @@
+package assets
+
+import "testing"
+
+func TestExtForContentType(t *testing.T) {
+	tests := []struct {
+		ct   string
+		want string
+	}{
+		{"image/jpeg", ".jpg"},
+		{"image/jpg", ".jpg"},
+		{"image/pjpeg", ".jpg"},
+		{"image/png", ".png"},
+		{"image/webp", ".webp"},
+		{"image/gif", ".gif"},
+		{"image/avif", ".avif"},
+		{"image/svg+xml", ".svg"},
+		{"image/bmp", ".bmp"},
+		{"image/x-ms-bmp", ".bmp"},
+		{"image/tiff", ".tiff"},
+		{"IMAGE/PNG", ".png"},
+		{"image/png; charset=binary", ".png"},
+		{" image/webp ;q=1", ".webp"},
+		{"application/octet-stream", ".jpg"},
+		{"", ".jpg"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ct, func(t *testing.T) {
+			if got := ExtForContentType(tt.ct); got != tt.want {
+				t.Errorf("ExtForContentType(%q) = %q, want %q", tt.ct, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtForContentTypeCoversImageExts(t *testing.T) {
+	for ct, ext := range imageExts {
+		if got := ExtForContentType(ct); got != ext {
+			t.Errorf("ExtForContentType(%q) = %q, want %q", ct, got, ext)
+		}
+	}
+}
+
+func TestContentTypeFor(t *testing.T) {
+	tests := []struct {
+		name      string
+		ct        string
+		sourceURL string
+		want      string
+	}{
+		{"recognized type is kept", "image/png", "https://example.com/a.jpg", "image/png"},
+		{"recognized type keeps parameters", "image/png; charset=binary", "https://example.com/a.jpg", "image/png; charset=binary"},
+		{"octet-stream uses .jpg", "application/octet-stream", "https://example.com/a.jpg", "image/jpeg"},
+		{"octet-stream uses .jpeg", "application/octet-stream", "https://example.com/a.jpeg", "image/jpeg"},
+		{"empty uses .png", "", "https://example.com/a.png", "image/png"},
+		{"empty uses .webp", "", "https://example.com/a.webp", "image/webp"},
+		{"empty uses .gif", "", "https://example.com/a.gif", "image/gif"},
+		{"empty uses .avif", "", "https://example.com/a.avif", "image/avif"},
+		{"empty uses .svg", "", "https://example.com/a.svg", "image/svg+xml"},
+		{"empty uses .bmp", "", "https://example.com/a.bmp", "image/bmp"},
+		{"empty uses .tif", "", "https://example.com/a.tif", "image/tiff"},
+		{"empty uses .tiff", "", "https://example.com/a.tiff", "image/tiff"},
+		{"extension is case-insensitive", "", "https://example.com/A.PNG", "image/png"},
+		{"query string is ignored", "binary/octet-stream", "https://example.com/a.png?v=2&x=.gif", "image/png"},
+		{"fragment is ignored", "", "https://example.com/a.webp#frag", "image/webp"},
+		{"unknown extension keeps ct", "application/octet-stream", "https://example.com/a.txt", "application/octet-stream"},
+		{"no extension keeps ct", "text/html", "https://example.com/avatar", "text/html"},
+		{"unparseable URL keeps ct", "", "://bad url.png", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ContentTypeFor(tt.ct, tt.sourceURL); got != tt.want {
+				t.Errorf("ContentTypeFor(%q, %q) = %q, want %q", tt.ct, tt.sourceURL, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		opts SlugOptions
+		want string
+	}{
+		{"default separator, lowercased", "Jane Doe", SlugOptions{}, "jane-doe"},
+		{"default separator, case kept", "Jane Doe", SlugOptions{PreserveCase: true}, "Jane-Doe"},
+		{"dash, lowercased", "Jane Doe", SlugOptions{Separator: "-"}, "jane-doe"},
+		{"dash, case kept", "Jane Doe", SlugOptions{Separator: "-", PreserveCase: true}, "Jane-Doe"},
+		{"underscore, lowercased", "Jane Doe", SlugOptions{Separator: "_"}, "jane_doe"},
+		{"underscore, case kept", "Jane Doe", SlugOptions{Separator: "_", PreserveCase: true}, "Jane_Doe"},
+		{"dot, lowercased", "Jane Doe", SlugOptions{Separator: "."}, "jane.doe"},
+		{"dot, case kept", "Jane Doe", SlugOptions{Separator: ".", PreserveCase: true}, "Jane.Doe"},
+		{"multi-character separator", "Jane Doe", SlugOptions{Separator: "--"}, "jane--doe"},
+		{"every space is replaced", "Mary  Ann Smith", SlugOptions{Separator: "_"}, "mary__ann_smith"},
+		{"surrounding spaces are trimmed", "  Jane Doe  ", SlugOptions{Separator: "_"}, "jane_doe"},
+		{"punctuation is dropped", "Jane O'Doe, PhD.", SlugOptions{}, "jane-odoe-phd"},
+		{"accented letters are dropped", "José Pérez", SlugOptions{PreserveCase: true}, "Jos-Prez"},
+		{"dashes and underscores are kept", "Anne-Marie le_Roux", SlugOptions{Separator: "_", PreserveCase: true}, "Anne-Marie_le_Roux"},
+		{"digits are kept", "Agent 47", SlugOptions{Separator: "."}, "agent.47"},
+		{"empty name", "", SlugOptions{Separator: "_"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Slugify(tt.in, tt.opts); got != tt.want {
+				t.Errorf("Slugify(%q, %+v) = %q, want %q", tt.in, tt.opts, got, tt.want)
+			}
+		})
+	}
+}
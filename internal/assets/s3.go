@@ -0,0 +1,134 @@
+package assets
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// S3Config configures an S3-compatible bucket (AWS S3, Cloudflare R2, MinIO).
+type S3Config struct {
+	Bucket string
+	Region string
+	// Endpoint is the API base URL. Defaults to https://s3.<region>.amazonaws.com.
+	Endpoint        string
+	AccessKeyID     string
+	SecretAccessKey string
+	// PublicBaseURL is the base that objects are served from. Defaults to
+	// the path-style object URL on Endpoint.
+	PublicBaseURL string
+	// Prefix is prepended to every object key, e.g. "avatars/".
+	Prefix string
+}
+
+// S3Uploader uploads assets to an S3-compatible bucket using SigV4-signed
+// PUT requests. It implements AssetUploader.
+type S3Uploader struct {
+	cfg        S3Config
+	Slug       SlugOptions
+	HTTPClient *http.Client
+}
+
+// NewS3Uploader creates an uploader for the given bucket.
+func NewS3Uploader(cfg S3Config) *S3Uploader {
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", cfg.Region)
+	}
+	cfg.Endpoint = strings.TrimRight(cfg.Endpoint, "/")
+	cfg.PublicBaseURL = strings.TrimRight(cfg.PublicBaseURL, "/")
+	return &S3Uploader{
+		cfg:        cfg,
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Upload PUTs data into the bucket and returns its public URL.
+func (u *S3Uploader) Upload(ctx context.Context, data []byte, contentType, name string) (string, error) {
+	key := u.cfg.Prefix + Slugify(name, u.Slug) + ExtForContentType(contentType)
+	objectURL := fmt.Sprintf("%s/%s/%s", u.cfg.Endpoint, u.cfg.Bucket, key)
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", objectURL, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", contentType)
+	u.sign(req, data, time.Now().UTC())
+
+	resp, err := u.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("s3 put: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return "", fmt.Errorf("s3 put failed (%d): could not read body: %w", resp.StatusCode, err)
+		}
+		return "", fmt.Errorf("s3 put failed (%d): %s", resp.StatusCode, string(body))
+	}
+
+	if u.cfg.PublicBaseURL != "" {
+		return u.cfg.PublicBaseURL + "/" + key, nil
+	}
+	return objectURL, nil
+}
+
+// sign adds AWS Signature Version 4 headers to req.
+func (u *S3Uploader) sign(req *http.Request, payload []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	signedHeaders := "content-type;host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "content-type:" + req.Header.Get("Content-Type") + "\n" +
+		"host:" + req.URL.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		(&url.URL{Path: req.URL.Path}).EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + u.cfg.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+u.cfg.SecretAccessKey), date)
+	key = hmacSHA256(key, u.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		u.cfg.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
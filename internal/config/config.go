@@ -1,47 +1,267 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
+
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/assets"
+	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	SpaceID        string
-	CMAToken       string
+	SpaceID  string
+	CMAToken string
+	// CDAToken is a Content Delivery API token. Read-only commands prefer
+	// it to the CMA token when set.
+	CDAToken       string
 	LinkedInCookie string
 	GeminiAPIKey   string
+	Locale         string
+	Environment    string
+	// ContentfulBaseURL and ContentfulUploadBaseURL override the CMA and
+	// upload hosts, e.g. for EU-hosted spaces. Empty means the client's
+	// defaults.
+	ContentfulBaseURL       string
+	ContentfulUploadBaseURL string
+	// SectionID and SectionTitle pick the siteSection entry holding the
+	// testimonials. Empty means the client's defaults.
+	SectionID    string
+	SectionTitle string
+	S3           assets.S3Config
+	// VoyagerBaseURL and RestliProtocolVersion override the scraper's
+	// defaults when set.
+	VoyagerBaseURL        string
+	RestliProtocolVersion string
+}
+
+// Overrides holds command-line values that take precedence over env vars.
+// Empty fields are ignored.
+type Overrides struct {
+	SpaceID  string
+	CMAToken string
+	// ConfigFile is a file read with LoadFile whose values sit below env
+	// vars.
+	ConfigFile string
+	// ReadOnly accepts a CDA token in place of the CMA token, for commands
+	// that only read from Contentful.
+	ReadOnly bool
+}
+
+// File is a config file. Every field falls back to it when the matching
+// env var is unset.
+type File struct {
+	SpaceID        string `yaml:"spaceID"`
+	CMAToken       string `yaml:"cmaToken"`
+	CDAToken       string `yaml:"cdaToken"`
+	LinkedInCookie string `yaml:"linkedInCookie"`
+	GeminiAPIKey   string `yaml:"geminiApiKey"`
+	Environment    string `yaml:"environment"`
+	Locale         string `yaml:"locale"`
+	BaseURL        string `yaml:"baseURL"`
+	UploadBaseURL  string `yaml:"uploadBaseURL"`
+	SectionID      string `yaml:"sectionID"`
+	SectionTitle   string `yaml:"sectionTitle"`
+}
+
+// LoadFile reads a YAML or JSON config file. Unknown keys are rejected so
+// typos don't silently fall back to env vars.
+func LoadFile(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var file File
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return &file, nil
+}
+
+// file loads o.ConfigFile, or returns an empty File when none is set.
+func (o Overrides) file() (*File, error) {
+	if o.ConfigFile == "" {
+		return &File{}, nil
+	}
+	return LoadFile(o.ConfigFile)
+}
+
+// envOr returns the env var key, or fallback when it is unset or empty.
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
 }
 
 // Load loads all config including LinkedIn cookie (for scrape command).
-func Load() (*Config, error) {
-	cfg, err := LoadContentful()
+func Load(o Overrides) (*Config, error) {
+	file, err := o.file()
 	if err != nil {
 		return nil, err
 	}
+	cfg, err := loadContentful(o, file)
+	if err != nil {
+		return nil, err
+	}
+	if err := loadLinkedIn(cfg, file); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// LoadLinkedIn loads only the LinkedIn side of the config (for commands that
+// scrape without touching Contentful).
+func LoadLinkedIn(o Overrides) (*Config, error) {
+	file, err := o.file()
+	if err != nil {
+		return nil, err
+	}
+	cfg := &Config{}
+	if err := loadLinkedIn(cfg, file); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
 
-	cfg.LinkedInCookie = os.Getenv("LINKEDIN_COOKIE")
+func loadLinkedIn(cfg *Config, file *File) error {
+	cfg.LinkedInCookie = envOr("LINKEDIN_COOKIE", file.LinkedInCookie)
 	if cfg.LinkedInCookie == "" {
-		return nil, fmt.Errorf("LINKEDIN_COOKIE (li_at value) is required")
+		return fmt.Errorf("LINKEDIN_COOKIE (li_at value) is required")
 	}
 
-	cfg.GeminiAPIKey = os.Getenv("GEMINI_API_KEY")
+	cfg.GeminiAPIKey = envOr("GEMINI_API_KEY", file.GeminiAPIKey)
+	cfg.VoyagerBaseURL = os.Getenv("LINKEDIN_VOYAGER_BASE_URL")
+	cfg.RestliProtocolVersion = os.Getenv("LINKEDIN_RESTLI_PROTOCOL_VERSION")
+
+	cfg.S3 = assets.S3Config{
+		Bucket:          os.Getenv("S3_BUCKET"),
+		Region:          os.Getenv("S3_REGION"),
+		Endpoint:        os.Getenv("S3_ENDPOINT"),
+		AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+		PublicBaseURL:   os.Getenv("S3_PUBLIC_URL"),
+		Prefix:          os.Getenv("S3_PREFIX"),
+	}
+	return nil
+}
 
+// RequireGemini checks that a Gemini API key is set, for commands that
+// translate. Load doesn't require one since most runs don't translate.
+func (c *Config) RequireGemini() error {
+	if c.GeminiAPIKey == "" {
+		return fmt.Errorf("GEMINI_API_KEY (or geminiApiKey in the config file) is required for translation")
+	}
+	return nil
+}
+
+// LoadGemini loads only the Gemini API key (for the translate command) and
+// requires it to be set.
+func LoadGemini(o Overrides) (*Config, error) {
+	file, err := o.file()
+	if err != nil {
+		return nil, err
+	}
+	cfg := &Config{GeminiAPIKey: envOr("GEMINI_API_KEY", file.GeminiAPIKey)}
+	if err := cfg.RequireGemini(); err != nil {
+		return nil, err
+	}
 	return cfg, nil
 }
 
 // LoadContentful loads only Contentful config (for list command).
-func LoadContentful() (*Config, error) {
+func LoadContentful(o Overrides) (*Config, error) {
+	file, err := o.file()
+	if err != nil {
+		return nil, err
+	}
+	return loadContentful(o, file)
+}
+
+func loadContentful(o Overrides, file *File) (*Config, error) {
 	cfg := &Config{
-		SpaceID:  os.Getenv("CONTENTFUL_SPACE_ID"),
-		CMAToken: os.Getenv("CONTENTFUL_CMA_TOKEN"),
+		SpaceID:     envOr("CONTENTFUL_SPACE_ID", file.SpaceID),
+		CMAToken:    envOr("CONTENTFUL_CMA_TOKEN", file.CMAToken),
+		CDAToken:    envOr("CONTENTFUL_CDA_TOKEN", file.CDAToken),
+		Locale:      envOr("CONTENTFUL_LOCALE", file.Locale),
+		Environment: envOr("CONTENTFUL_ENVIRONMENT", file.Environment),
+
+		ContentfulBaseURL:       envOr("CONTENTFUL_BASE_URL", file.BaseURL),
+		ContentfulUploadBaseURL: envOr("CONTENTFUL_UPLOAD_BASE_URL", file.UploadBaseURL),
+
+		SectionID:    envOr("CONTENTFUL_SECTION_ID", file.SectionID),
+		SectionTitle: envOr("CONTENTFUL_SECTION_TITLE", file.SectionTitle),
+	}
+
+	if o.SpaceID != "" {
+		cfg.SpaceID = o.SpaceID
+	}
+	if o.CMAToken != "" {
+		cfg.CMAToken = o.CMAToken
+	}
+
+	if cfg.Locale == "" {
+		cfg.Locale = "en-US"
+	}
+	if cfg.Environment == "" {
+		cfg.Environment = "master"
 	}
 
 	if cfg.SpaceID == "" {
 		return nil, fmt.Errorf("CONTENTFUL_SPACE_ID is required")
 	}
-	if cfg.CMAToken == "" {
+	if cfg.CMAToken == "" && !(o.ReadOnly && cfg.CDAToken != "") {
+		if o.ReadOnly {
+			return nil, fmt.Errorf("CONTENTFUL_CMA_TOKEN or CONTENTFUL_CDA_TOKEN is required")
+		}
 		return nil, fmt.Errorf("CONTENTFUL_CMA_TOKEN is required")
 	}
+	if err := checkHostURL(cfg.ContentfulBaseURL); err != nil {
+		return nil, fmt.Errorf("CONTENTFUL_BASE_URL: %w", err)
+	}
+	if err := checkHostURL(cfg.ContentfulUploadBaseURL); err != nil {
+		return nil, fmt.Errorf("CONTENTFUL_UPLOAD_BASE_URL: %w", err)
+	}
 
 	return cfg, nil
 }
+
+// checkHostURL accepts an empty string or an absolute https URL with no
+// query or fragment.
+func checkHostURL(raw string) error {
+	if raw == "" {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "https" || u.Host == "" || u.RawQuery != "" || u.Fragment != "" {
+		return fmt.Errorf("want an https URL like https://api.eu.contentful.com, got %q", raw)
+	}
+	return nil
+}
+
+// String returns a printable form of the config with secrets redacted.
+func (c *Config) String() string {
+	return fmt.Sprintf("space=%s environment=%s locale=%s section=%s cmaToken=%s cdaToken=%s linkedInCookie=%s geminiAPIKey=%s s3Bucket=%s s3SecretKey=%s",
+		c.SpaceID, c.Environment, c.Locale, c.SectionID, redact(c.CMAToken), redact(c.CDAToken), redact(c.LinkedInCookie), redact(c.GeminiAPIKey),
+		c.S3.Bucket, redact(c.S3.SecretAccessKey))
+}
+
+// redact hides all but the last four characters of a secret.
+func redact(secret string) string {
+	if secret == "" {
+		return "(unset)"
+	}
+	if len(secret) <= 4 {
+		return "****"
+	}
+	return "****" + secret[len(secret)-4:]
+}
@@ -0,0 +1,182 @@
+package contentful
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	servicekit "github.com/alberto-moreno-sa/go-service-kit/contentful"
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/httpx"
+)
+
+// GetBuildLog fetches the build log entry. It shadows the service kit method
+// so entries keep fields (such as ContentHash) the kit doesn't know about.
+func (c *Client) GetBuildLog(ctx context.Context) (*BuildLogResult, error) {
+	endpoint := fmt.Sprintf("%s/spaces/%s/environments/%s/entries", c.baseURL(), c.SpaceID, c.Environment)
+
+	params := url.Values{}
+	params.Set("content_type", "buildLog")
+	params.Set("limit", "1")
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+
+	resp, err := c.doWithRetry(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("CMA build log query failed (%d): could not read body: %w", resp.StatusCode, err)
+		}
+		return nil, &httpx.StatusError{Op: "CMA build log query", StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	var result servicekit.EntriesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode build log response: %w", err)
+	}
+
+	if len(result.Items) == 0 {
+		return &BuildLogResult{}, nil
+	}
+
+	entry := result.Items[0]
+	logResult := &BuildLogResult{
+		EntryID:   entry.Sys.ID,
+		Version:   entry.Sys.Version,
+		RawFields: entry.Fields,
+	}
+
+	localeMap, ok := entry.Fields["logInfo"].(map[string]interface{})
+	if !ok {
+		return logResult, nil
+	}
+
+	// Entries written by the service kit sit under DefaultLocale, so fall
+	// back to it when the configured locale has no value.
+	rawContent, ok := localeMap[c.Locale]
+	if !ok {
+		rawContent, _, _ = pickLocale(localeMap, DefaultLocale)
+	}
+
+	contentBytes, err := json.Marshal(rawContent)
+	if err != nil {
+		return nil, fmt.Errorf("marshal build log content: %w", err)
+	}
+
+	if err := json.Unmarshal(contentBytes, &logResult.Entries); err != nil {
+		return nil, fmt.Errorf("unmarshal build log entries: %w", err)
+	}
+
+	return logResult, nil
+}
+
+// UpdateBuildLog updates the build log entry using the fetch-mutate-put pattern.
+func (c *Client) UpdateBuildLog(ctx context.Context, result *BuildLogResult, entries []BuildLogEntry) (int, error) {
+	endpoint := fmt.Sprintf("%s/spaces/%s/environments/%s/entries/%s",
+		c.baseURL(), c.SpaceID, c.Environment, result.EntryID)
+
+	fields := make(map[string]interface{})
+	for k, v := range result.RawFields {
+		fields[k] = v
+	}
+	// Other locales' values are kept; only the configured locale is rewritten.
+	logInfo := make(map[string]interface{})
+	if localeMap, ok := result.RawFields["logInfo"].(map[string]interface{}); ok {
+		for k, v := range localeMap {
+			logInfo[k] = v
+		}
+	}
+	logInfo[c.Locale] = entries
+	fields["logInfo"] = logInfo
+
+	bodyBytes, err := json.Marshal(map[string]interface{}{"fields": fields})
+	if err != nil {
+		return 0, fmt.Errorf("marshal build log body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", endpoint, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	req.Header.Set("Content-Type", "application/vnd.contentful.management.v1+json")
+	req.Header.Set("X-Contentful-Version", fmt.Sprintf("%d", result.Version))
+
+	resp, err := c.doWithRetry(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return 0, fmt.Errorf("CMA build log update failed (%d): could not read body: %w", resp.StatusCode, err)
+		}
+		return 0, &httpx.StatusError{Op: "CMA build log update", StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	var updated servicekit.EntryItem
+	if err := json.NewDecoder(resp.Body).Decode(&updated); err != nil {
+		return 0, fmt.Errorf("decode build log update response: %w", err)
+	}
+
+	return updated.Sys.Version, nil
+}
+
+// CreateBuildLog creates a new buildLog entry.
+func (c *Client) CreateBuildLog(ctx context.Context, entries []BuildLogEntry) (string, int, error) {
+	endpoint := fmt.Sprintf("%s/spaces/%s/environments/%s/entries", c.baseURL(), c.SpaceID, c.Environment)
+
+	body := map[string]interface{}{
+		"fields": map[string]interface{}{
+			"logInfo": map[string]interface{}{c.Locale: entries},
+		},
+	}
+
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return "", 0, fmt.Errorf("marshal build log body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	req.Header.Set("Content-Type", "application/vnd.contentful.management.v1+json")
+	req.Header.Set("X-Contentful-Content-Type", "buildLog")
+
+	resp, err := c.doWithRetry(req)
+	if err != nil {
+		return "", 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 201 {
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return "", 0, fmt.Errorf("CMA build log create failed (%d): could not read body: %w", resp.StatusCode, err)
+		}
+		return "", 0, &httpx.StatusError{Op: "CMA build log create", StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	var created servicekit.EntryItem
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return "", 0, fmt.Errorf("decode build log create response: %w", err)
+	}
+
+	return created.Sys.ID, created.Sys.Version, nil
+}
@@ -0,0 +1,202 @@
+package contentful
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/httpx"
+)
+
+// PendingAssets returns the IDs of assets uploaded with DeferAssetPublish
+// that have not been published yet.
+func (c *Client) PendingAssets() []string {
+	ids := make([]string, 0, len(c.pendingAssets))
+	for id := range c.pendingAssets {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// PublishAssetsBulk publishes the given assets with a single bulk action.
+// If the bulk action can't be created or fails, each asset is published
+// individually instead. Either way every asset is then checked to have
+// reached the published state.
+func (c *Client) PublishAssetsBulk(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	versions := make(map[string]int, len(ids))
+	for _, id := range ids {
+		version, ok := c.pendingAssets[id]
+		if !ok {
+			a, err := c.getAsset(ctx, id)
+			if err != nil {
+				return fmt.Errorf("asset %s: %w", id, err)
+			}
+			version = a.Sys.Version
+		}
+		versions[id] = version
+	}
+
+	if bulkErr := c.runBulkPublish(ctx, ids, versions); bulkErr != nil {
+		var errs []error
+		for _, id := range ids {
+			if err := c.publishAsset(ctx, id, versions[id]); err != nil {
+				errs = append(errs, fmt.Errorf("asset %s: %w", id, err))
+			}
+		}
+		if len(errs) > 0 {
+			return fmt.Errorf("bulk publish: %v; individual publish: %w", bulkErr, errors.Join(errs...))
+		}
+	}
+
+	var unpublished []string
+	for _, id := range ids {
+		a, err := c.getAsset(ctx, id)
+		if err != nil || a.Sys.PublishedVersion == 0 {
+			unpublished = append(unpublished, id)
+			continue
+		}
+		delete(c.pendingAssets, id)
+	}
+	if len(unpublished) > 0 {
+		return fmt.Errorf("assets not published: %s", strings.Join(unpublished, ", "))
+	}
+	return nil
+}
+
+// runBulkPublish creates a bulk publish action and waits for it to finish.
+func (c *Client) runBulkPublish(ctx context.Context, ids []string, versions map[string]int) error {
+	type linkSys struct {
+		Type     string `json:"type"`
+		LinkType string `json:"linkType"`
+		ID       string `json:"id"`
+		Version  int    `json:"version"`
+	}
+	type link struct {
+		Sys linkSys `json:"sys"`
+	}
+	items := make([]link, len(ids))
+	for i, id := range ids {
+		items[i] = link{Sys: linkSys{Type: "Link", LinkType: "Asset", ID: id, Version: versions[id]}}
+	}
+	body := map[string]interface{}{
+		"entities": map[string]interface{}{
+			"sys":   map[string]string{"type": "Array"},
+			"items": items,
+		},
+	}
+
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshal bulk action: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/spaces/%s/environments/%s/bulk_actions/publish",
+		c.baseURL(), c.SpaceID, c.Environment)
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	req.Header.Set("Content-Type", "application/vnd.contentful.management.v1+json")
+
+	resp, err := c.doWithRetry(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 && resp.StatusCode != 201 {
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("CMA bulk publish failed (%d): could not read body: %w", resp.StatusCode, err)
+		}
+		return &httpx.StatusError{Op: "CMA bulk publish", StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	var action bulkAction
+	if err := json.NewDecoder(resp.Body).Decode(&action); err != nil {
+		return fmt.Errorf("decode bulk action: %w", err)
+	}
+
+	actionEndpoint := fmt.Sprintf("%s/spaces/%s/environments/%s/bulk_actions/actions/%s",
+		c.baseURL(), c.SpaceID, c.Environment, action.Sys.ID)
+	for i := 0; i < 20; i++ {
+		switch action.Sys.Status {
+		case "succeeded":
+			return nil
+		case "failed":
+			return fmt.Errorf("bulk action %s failed", action.Sys.ID)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(500 * time.Millisecond):
+		}
+
+		if err := c.getJSON(ctx, actionEndpoint, &action); err != nil {
+			return fmt.Errorf("poll bulk action: %w", err)
+		}
+	}
+	return fmt.Errorf("bulk action %s still %s", action.Sys.ID, action.Sys.Status)
+}
+
+type bulkAction struct {
+	Sys struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	} `json:"sys"`
+}
+
+type assetItem struct {
+	Sys struct {
+		Version          int `json:"version"`
+		PublishedVersion int `json:"publishedVersion"`
+	} `json:"sys"`
+}
+
+// getAsset fetches an asset's sys metadata.
+func (c *Client) getAsset(ctx context.Context, id string) (*assetItem, error) {
+	endpoint := fmt.Sprintf("%s/spaces/%s/environments/%s/assets/%s",
+		c.baseURL(), c.SpaceID, c.Environment, id)
+	var a assetItem
+	if err := c.getJSON(ctx, endpoint, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// getJSON issues an authenticated CMA GET and decodes the response into v.
+func (c *Client) getJSON(ctx context.Context, endpoint string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+
+	resp, err := c.doWithRetry(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("CMA get failed (%d): could not read body: %w", resp.StatusCode, err)
+		}
+		return &httpx.StatusError{Op: "CMA get", StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
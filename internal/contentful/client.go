@@ -3,36 +3,204 @@ package contentful
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"reflect"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
 	servicekit "github.com/alberto-moreno-sa/go-service-kit/contentful"
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/assets"
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/httpx"
 )
 
+const (
+	// DefaultLocale is the locale used when none is configured.
+	DefaultLocale = "en-US"
+	// DefaultEnvironment is the environment used when none is configured.
+	DefaultEnvironment = "master"
+	// DefaultSectionID is the siteSection sectionId holding testimonials.
+	DefaultSectionID = "testimonials"
+	// DefaultSectionTitle is the title given to a newly created section.
+	DefaultSectionTitle = "Testimonials"
+	// DefaultAssetPollInterval and DefaultAssetPollAttempts control how
+	// Upload waits for Contentful to finish processing an asset.
+	DefaultAssetPollInterval = 500 * time.Millisecond
+	DefaultAssetPollAttempts = 20
+	// DefaultBaseURL and DefaultUploadBaseURL are the CMA and upload hosts
+	// of spaces in Contentful's default (US) region. Spaces hosted in the
+	// EU use EUBaseURL and EUUploadBaseURL; the two hosts always come from
+	// the same region.
+	DefaultBaseURL       = servicekit.CMABaseURL
+	DefaultUploadBaseURL = "https://upload.contentful.com"
+	EUBaseURL            = "https://api.eu.contentful.com"
+	EUUploadBaseURL      = "https://upload.eu.contentful.com"
+)
+
+// ErrVersionConflict is returned by UpdateTestimonials when the entry
+// changed since it was fetched. The caller should fetch it again and
+// recompute the testimonials from the fresh content.
+var ErrVersionConflict = errors.New("testimonials entry changed since it was read")
+
 // Client embeds the SDK client and adds testimonial-specific methods.
 type Client struct {
 	*servicekit.Client
+
+	// SectionID and SectionTitle identify the siteSection entry that holds
+	// the testimonials. They default to DefaultSectionID and DefaultSectionTitle.
+	SectionID    string
+	SectionTitle string
+
+	// Locale is the locale code entry and asset fields are read from and
+	// written to. Defaults to en-US.
+	Locale string
+
+	// Environment is the Contentful environment every request targets.
+	// Defaults to master.
+	Environment string
+
+	// BaseURL is the CMA host, e.g. EUBaseURL for EU-hosted spaces.
+	// Defaults to DefaultBaseURL. UploadBaseURL is the matching upload
+	// host; when empty it is derived from BaseURL with UploadBaseURLFor.
+	BaseURL       string
+	UploadBaseURL string
+
+	// Slug controls how avatar file names are derived from names.
+	Slug assets.SlugOptions
+
+	// AvatarSink stores avatar images. Nil means the Contentful asset
+	// library via Upload.
+	AvatarSink assets.AssetUploader
+
+	// SquareAvatars center-crops uploaded avatars to a square.
+	SquareAvatars bool
+
+	// MaxAvatarSize, when positive, shrinks avatars wider or taller than
+	// this many pixels and re-encodes them as JPEG (PNG if transparent).
+	// Animated GIFs are uploaded as is.
+	MaxAvatarSize int
+
+	// VerifyAvatars makes UploadAvatar wait until the published CDN URL
+	// responds with 200 before returning it.
+	VerifyAvatars bool
+
+	// DeferAssetPublish makes Upload leave assets unpublished and record
+	// them for PublishAssetsBulk.
+	DeferAssetPublish bool
+
+	// DraftReferences leaves testimonial entries created or updated under
+	// ModelReferences as drafts instead of publishing them.
+	DraftReferences bool
+
+	// Model is how the content field stores testimonials: ModelEmbedded
+	// (the default) or ModelReferences.
+	Model string
+
+	// AssetPollInterval is the initial pause between checks on whether an
+	// uploaded asset has been processed, and AssetPollAttempts the number
+	// of checks. Later checks back off to up to 4x the interval.
+	AssetPollInterval time.Duration
+	AssetPollAttempts int
+
+	// MaxRetries is how many times a CMA request answered with 429 or 5xx
+	// is retried. Defaults to DefaultMaxRetries.
+	MaxRetries int
+
+	// Tags are the IDs of Contentful tags attached to the testimonials
+	// entry and uploaded avatar assets, marking them as synced content. The
+	// tags must already exist in the environment. Empty sends no metadata.
+	Tags []string
+
+	// Logger receives retries, conflicts and other non-fatal problems.
+	// Nil discards them.
+	Logger *slog.Logger
+
+	// pendingAssets maps deferred asset IDs to their latest version.
+	pendingAssets map[string]int
 }
 
 // NewClient creates a new Contentful client with SDK and testimonial support.
+// Requests use an HTTP client with httpx.DefaultTimeout.
 func NewClient(spaceID, token string) *Client {
+	return NewClientWithHTTP(spaceID, token, nil)
+}
+
+// NewClientWithHTTP is like NewClient but sends every request, including
+// avatar downloads, through hc. A nil hc gets the NewClient default.
+func NewClientWithHTTP(spaceID, token string, hc *http.Client) *Client {
+	if hc == nil {
+		hc = httpx.NewClient(0)
+	}
+	sk := servicekit.NewClient(spaceID, token)
+	sk.HTTPClient = hc
 	return &Client{
-		Client: servicekit.NewClient(spaceID, token),
+		Client:            sk,
+		SectionID:         DefaultSectionID,
+		SectionTitle:      DefaultSectionTitle,
+		Locale:            DefaultLocale,
+		Environment:       DefaultEnvironment,
+		BaseURL:           DefaultBaseURL,
+		Model:             ModelEmbedded,
+		MaxRetries:        DefaultMaxRetries,
+		AssetPollInterval: DefaultAssetPollInterval,
+		AssetPollAttempts: DefaultAssetPollAttempts,
+	}
+}
+
+// baseURL returns the CMA host requests are sent to.
+func (c *Client) baseURL() string {
+	if c.BaseURL == "" {
+		return DefaultBaseURL
 	}
+	return strings.TrimRight(c.BaseURL, "/")
+}
+
+// uploadBaseURL returns the host avatar binaries are uploaded to.
+func (c *Client) uploadBaseURL() string {
+	if c.UploadBaseURL == "" {
+		return UploadBaseURLFor(c.baseURL())
+	}
+	return strings.TrimRight(c.UploadBaseURL, "/")
+}
+
+// UploadBaseURLFor returns the upload host paired with a CMA host:
+// api.<region>.contentful.com uploads to upload.<region>.contentful.com.
+// Hosts that don't follow that pattern get DefaultUploadBaseURL.
+func UploadBaseURLFor(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil || !strings.HasPrefix(u.Host, "api.") {
+		return DefaultUploadBaseURL
+	}
+	u.Host = "upload." + strings.TrimPrefix(u.Host, "api.")
+	u.Path = ""
+	return u.String()
+}
+
+// logger returns c.Logger, or a logger that discards everything when it
+// is nil.
+func (c *Client) logger() *slog.Logger {
+	if c.Logger == nil {
+		return slog.New(slog.DiscardHandler)
+	}
+	return c.Logger
 }
 
 // GetTestimonials fetches the testimonials siteSection entry.
 func (c *Client) GetTestimonials(ctx context.Context) (*TestimonialsResult, error) {
-	endpoint := fmt.Sprintf("%s/spaces/%s/environments/master/entries", servicekit.CMABaseURL, c.SpaceID)
+	endpoint := fmt.Sprintf("%s/spaces/%s/environments/%s/entries", c.baseURL(), c.SpaceID, c.Environment)
 
 	params := url.Values{}
 	params.Set("content_type", "siteSection")
-	params.Set("fields.sectionId", "testimonials")
+	params.Set("fields.sectionId", c.SectionID)
 	params.Set("limit", "1")
 
 	req, err := http.NewRequestWithContext(ctx, "GET", endpoint+"?"+params.Encode(), nil)
@@ -41,7 +209,7 @@ func (c *Client) GetTestimonials(ctx context.Context) (*TestimonialsResult, erro
 	}
 	req.Header.Set("Authorization", "Bearer "+c.Token)
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.doWithRetry(req)
 	if err != nil {
 		return nil, err
 	}
@@ -52,10 +220,10 @@ func (c *Client) GetTestimonials(ctx context.Context) (*TestimonialsResult, erro
 		if err != nil {
 			return nil, fmt.Errorf("CMA query failed (%d): could not read body: %w", resp.StatusCode, err)
 		}
-		return nil, fmt.Errorf("CMA query failed (%d): %s", resp.StatusCode, string(body))
+		return nil, &httpx.StatusError{Op: "CMA query", StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
-	var result servicekit.EntriesResponse
+	var result entriesResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
@@ -66,66 +234,166 @@ func (c *Client) GetTestimonials(ctx context.Context) (*TestimonialsResult, erro
 
 	entry := result.Items[0]
 
+	fieldLocales := make(map[string]string)
+	for name, field := range entry.Fields {
+		if localeMap, ok := field.(map[string]interface{}); ok {
+			if _, locale, ok := pickLocale(localeMap, c.Locale); ok {
+				fieldLocales[name] = locale
+			}
+		}
+	}
+
 	contentField, ok := entry.Fields["content"]
 	if !ok {
 		return &TestimonialsResult{
-			EntryID:   entry.Sys.ID,
-			Version:   entry.Sys.Version,
-			RawFields: entry.Fields,
+			EntryID:      entry.Sys.ID,
+			Version:      entry.Sys.Version,
+			RawFields:    entry.Fields,
+			TagIDs:       entry.tagIDs(),
+			FieldLocales: fieldLocales,
 		}, fmt.Errorf("entry has no 'content' field")
 	}
 
 	localeMap, ok := contentField.(map[string]interface{})
 	if !ok {
 		return &TestimonialsResult{
-			EntryID:   entry.Sys.ID,
-			Version:   entry.Sys.Version,
-			RawFields: entry.Fields,
+			EntryID:      entry.Sys.ID,
+			Version:      entry.Sys.Version,
+			RawFields:    entry.Fields,
+			TagIDs:       entry.tagIDs(),
+			FieldLocales: fieldLocales,
 		}, fmt.Errorf("content field is not locale-wrapped")
 	}
 
-	rawContent, ok := localeMap["en-US"]
-	if !ok {
-		for _, v := range localeMap {
-			rawContent = v
-			break
+	rawContent, _, _ := pickLocale(localeMap, c.Locale)
+
+	if c.Model == ModelReferences {
+		testimonials, linked, err := c.resolveLinkedEntries(ctx, rawContent)
+		if err != nil {
+			return nil, err
 		}
+		return &TestimonialsResult{
+			Testimonials:  testimonials,
+			EntryID:       entry.Sys.ID,
+			Version:       entry.Sys.Version,
+			RawFields:     entry.Fields,
+			TagIDs:        entry.tagIDs(),
+			FieldLocales:  fieldLocales,
+			LinkedEntries: linked,
+		}, nil
 	}
 
-	contentBytes, err := json.Marshal(rawContent)
+	testimonials, isString, err := decodeTestimonials(rawContent)
 	if err != nil {
-		return nil, fmt.Errorf("marshal content: %w", err)
+		return nil, err
 	}
 
-	var testimonials []Testimonial
+	return &TestimonialsResult{
+		Testimonials:    testimonials,
+		EntryID:         entry.Sys.ID,
+		Version:         entry.Sys.Version,
+		RawFields:       entry.Fields,
+		TagIDs:          entry.tagIDs(),
+		FieldLocales:    fieldLocales,
+		ContentIsString: isString,
+	}, nil
+}
+
+// decodeTestimonials decodes the content field's value. It is normally a
+// JSON array, but legacy entries keep the array as a JSON-encoded string
+// in a Text field; isString reports that shape.
+func decodeTestimonials(rawContent interface{}) (testimonials []Testimonial, isString bool, err error) {
+	if s, ok := rawContent.(string); ok {
+		if strings.TrimSpace(s) == "" {
+			return nil, true, nil
+		}
+		if err := json.Unmarshal([]byte(s), &testimonials); err != nil {
+			return nil, true, fmt.Errorf("content field is a string but not a JSON array of testimonials: %w", err)
+		}
+		return testimonials, true, nil
+	}
+
+	contentBytes, err := json.Marshal(rawContent)
+	if err != nil {
+		return nil, false, fmt.Errorf("marshal content: %w", err)
+	}
 	if err := json.Unmarshal(contentBytes, &testimonials); err != nil {
-		return nil, fmt.Errorf("unmarshal testimonials: %w", err)
+		return nil, false, fmt.Errorf("unmarshal testimonials: content field must be a JSON array or a string holding one: %w", err)
 	}
+	return testimonials, false, nil
+}
 
-	return &TestimonialsResult{
-		Testimonials: testimonials,
-		EntryID:      entry.Sys.ID,
-		Version:      entry.Sys.Version,
-		RawFields:    entry.Fields,
-	}, nil
+// pickLocale returns the value for the preferred locale, falling back to the
+// alphabetically first locale present so the choice is deterministic.
+func pickLocale(localeMap map[string]interface{}, preferred string) (interface{}, string, bool) {
+	if v, ok := localeMap[preferred]; ok {
+		return v, preferred, true
+	}
+	if len(localeMap) == 0 {
+		return nil, "", false
+	}
+	locales := make([]string, 0, len(localeMap))
+	for l := range localeMap {
+		locales = append(locales, l)
+	}
+	sort.Strings(locales)
+	return localeMap[locales[0]], locales[0], true
 }
 
 // UpdateTestimonials updates the testimonials entry using the fetch-mutate-put pattern.
+// When the stored content already equals testimonials no request is made
+// and the entry's current version is returned, so no new version is created.
+// If the entry changed since result was fetched (409), ErrVersionConflict
+// is returned and nothing is written.
 func (c *Client) UpdateTestimonials(ctx context.Context, result *TestimonialsResult, testimonials []Testimonial) (int, error) {
-	endpoint := fmt.Sprintf("%s/spaces/%s/environments/master/entries/%s",
-		servicekit.CMABaseURL, c.SpaceID, result.EntryID)
+	var content interface{} = testimonials
+	if c.Model == ModelReferences {
+		links, err := c.writeReferences(ctx, result.LinkedEntries, testimonials)
+		if err != nil {
+			return 0, err
+		}
+		content = links
+	} else if result.ContentIsString {
+		data, err := json.Marshal(testimonials)
+		if err != nil {
+			return 0, fmt.Errorf("marshal content: %w", err)
+		}
+		content = string(data)
+	}
+
+	if same, err := sameContent(result.RawFields, c.Locale, content); err != nil {
+		return 0, err
+	} else if same {
+		return result.Version, nil
+	}
+
+	version, err := c.putContent(ctx, result, content)
+	var statusErr *httpx.StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusConflict {
+		return 0, fmt.Errorf("entry %s at version %d: %w: %w", result.EntryID, result.Version, ErrVersionConflict, err)
+	}
+	return version, err
+}
+
+// putContent writes content into result's entry at result.Version, keeping
+// its other fields, and returns the new version.
+func (c *Client) putContent(ctx context.Context, result *TestimonialsResult, content interface{}) (int, error) {
+	endpoint := fmt.Sprintf("%s/spaces/%s/environments/%s/entries/%s",
+		c.baseURL(), c.SpaceID, c.Environment, result.EntryID)
 
 	fields := make(map[string]interface{})
 	for k, v := range result.RawFields {
 		fields[k] = v
 	}
-	fields["content"] = map[string]interface{}{
-		"en-US": testimonials,
-	}
+	fields["content"] = withLocale(result.RawFields["content"], c.Locale, content)
 
 	body := map[string]interface{}{
 		"fields": fields,
 	}
+	if len(c.Tags) > 0 {
+		// A PUT replaces the tag list, so keep tags added by others.
+		body["metadata"] = tagMetadata(append(slices.Clone(result.TagIDs), c.Tags...))
+	}
 
 	bodyBytes, err := json.Marshal(body)
 	if err != nil {
@@ -140,7 +408,7 @@ func (c *Client) UpdateTestimonials(ctx context.Context, result *TestimonialsRes
 	req.Header.Set("Content-Type", "application/vnd.contentful.management.v1+json")
 	req.Header.Set("X-Contentful-Version", fmt.Sprintf("%d", result.Version))
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.doWithRetry(req)
 	if err != nil {
 		return 0, err
 	}
@@ -151,7 +419,7 @@ func (c *Client) UpdateTestimonials(ctx context.Context, result *TestimonialsRes
 		if err != nil {
 			return 0, fmt.Errorf("CMA update failed (%d): could not read body: %w", resp.StatusCode, err)
 		}
-		return 0, fmt.Errorf("CMA update failed (%d): %s", resp.StatusCode, string(respBody))
+		return 0, &httpx.StatusError{Op: "CMA update", StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	var updated servicekit.EntryItem
@@ -162,17 +430,66 @@ func (c *Client) UpdateTestimonials(ctx context.Context, result *TestimonialsRes
 	return updated.Sys.Version, nil
 }
 
+// withLocale returns a copy of the locale-wrapped field with locale set to
+// v, keeping the values of every other locale.
+func withLocale(field interface{}, locale string, v interface{}) map[string]interface{} {
+	localized := make(map[string]interface{})
+	if existing, ok := field.(map[string]interface{}); ok {
+		for l, value := range existing {
+			localized[l] = value
+		}
+	}
+	localized[locale] = v
+	return localized
+}
+
+// sameContent reports whether the stored content field for locale encodes
+// the same JSON as content. Both sides are normalized through a generic
+// decode so key order and number formatting don't matter.
+func sameContent(rawFields map[string]interface{}, locale string, content interface{}) (bool, error) {
+	localeMap, ok := rawFields["content"].(map[string]interface{})
+	if !ok {
+		return false, nil
+	}
+	stored, ok := localeMap[locale]
+	if !ok {
+		return false, nil
+	}
+
+	data, err := json.Marshal(content)
+	if err != nil {
+		return false, fmt.Errorf("marshal content: %w", err)
+	}
+	var proposed interface{}
+	if err := json.Unmarshal(data, &proposed); err != nil {
+		return false, fmt.Errorf("normalize content: %w", err)
+	}
+	return reflect.DeepEqual(stored, proposed), nil
+}
+
 // CreateTestimonials creates a new siteSection entry for testimonials.
 func (c *Client) CreateTestimonials(ctx context.Context, testimonials []Testimonial) (string, int, error) {
-	endpoint := fmt.Sprintf("%s/spaces/%s/environments/master/entries", servicekit.CMABaseURL, c.SpaceID)
+	var content interface{} = testimonials
+	if c.Model == ModelReferences {
+		links, err := c.writeReferences(ctx, nil, testimonials)
+		if err != nil {
+			return "", 0, err
+		}
+		content = links
+	}
+
+	endpoint := fmt.Sprintf("%s/spaces/%s/environments/%s/entries", c.baseURL(), c.SpaceID, c.Environment)
 
 	body := map[string]interface{}{
 		"fields": map[string]interface{}{
-			"sectionId": map[string]interface{}{"en-US": "testimonials"},
-			"title":     map[string]interface{}{"en-US": "Testimonials"},
-			"content":   map[string]interface{}{"en-US": testimonials},
+			"sectionId": map[string]interface{}{c.Locale: c.SectionID},
+			"title":     map[string]interface{}{c.Locale: c.SectionTitle},
+			"content":   map[string]interface{}{c.Locale: content},
 		},
 	}
+	if len(c.Tags) > 0 {
+		body["metadata"] = tagMetadata(c.Tags)
+	}
 
 	bodyBytes, err := json.Marshal(body)
 	if err != nil {
@@ -187,7 +504,7 @@ func (c *Client) CreateTestimonials(ctx context.Context, testimonials []Testimon
 	req.Header.Set("Content-Type", "application/vnd.contentful.management.v1+json")
 	req.Header.Set("X-Contentful-Content-Type", "siteSection")
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.doWithRetry(req)
 	if err != nil {
 		return "", 0, err
 	}
@@ -198,7 +515,7 @@ func (c *Client) CreateTestimonials(ctx context.Context, testimonials []Testimon
 		if err != nil {
 			return "", 0, fmt.Errorf("CMA create failed (%d): could not read body: %w", resp.StatusCode, err)
 		}
-		return "", 0, fmt.Errorf("CMA create failed (%d): %s", resp.StatusCode, string(respBody))
+		return "", 0, &httpx.StatusError{Op: "CMA create", StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	var created servicekit.EntryItem
@@ -209,55 +526,115 @@ func (c *Client) CreateTestimonials(ctx context.Context, testimonials []Testimon
 	return created.Sys.ID, created.Sys.Version, nil
 }
 
-// UploadAvatar downloads an image from imageURL, uploads it to Contentful as an asset,
-// processes and publishes it, and returns the CDN URL.
-func (c *Client) UploadAvatar(ctx context.Context, imageURL, name string) (string, error) {
+// UploadAvatar downloads an image from imageURL, hands it to the avatar sink
+// (by default a published Contentful asset) and returns its public URL and,
+// for Contentful assets, the asset ID. Contentful assets are titled and
+// described from owner.
+// With the default sink, an already published asset holding the same image
+// for the same name is reused instead of uploading a duplicate.
+func (c *Client) UploadAvatar(ctx context.Context, imageURL string, owner AvatarOwner) (UploadedAsset, error) {
+	name := owner.Name
 	imgReq, err := http.NewRequestWithContext(ctx, "GET", imageURL, nil)
 	if err != nil {
-		return "", fmt.Errorf("create image request: %w", err)
+		return UploadedAsset{}, fmt.Errorf("create image request: %w", err)
 	}
 	imgResp, err := c.HTTPClient.Do(imgReq)
 	if err != nil {
-		return "", fmt.Errorf("download image: %w", err)
+		return UploadedAsset{}, fmt.Errorf("download image: %w", err)
 	}
 	defer imgResp.Body.Close()
 
 	if imgResp.StatusCode != 200 {
-		return "", fmt.Errorf("download image returned %d", imgResp.StatusCode)
+		return UploadedAsset{}, fmt.Errorf("download image returned %d", imgResp.StatusCode)
 	}
 
 	imgData, err := io.ReadAll(imgResp.Body)
 	if err != nil {
-		return "", fmt.Errorf("read image: %w", err)
+		return UploadedAsset{}, fmt.Errorf("read image: %w", err)
 	}
 
-	contentType := imgResp.Header.Get("Content-Type")
+	// The extension of imageURL fills in for a missing or generic type.
+	contentType := assets.ContentTypeFor(imgResp.Header.Get("Content-Type"), imageURL)
 	if contentType == "" {
 		contentType = "image/jpeg"
 	}
 
-	fileName := slugify(name) + extForContentType(contentType)
+	if c.SquareAvatars {
+		imgData, err = cropSquare(imgData)
+		if err != nil {
+			return UploadedAsset{}, fmt.Errorf("crop image: %w", err)
+		}
+	}
+	if c.MaxAvatarSize > 0 {
+		imgData, contentType, err = downscale(imgData, contentType, c.MaxAvatarSize)
+		if err != nil {
+			return UploadedAsset{}, fmt.Errorf("downscale image: %w", err)
+		}
+	}
+
+	sink := c.AvatarSink
+	if sink == nil {
+		existing, err := c.findExistingAsset(ctx, avatarFileName(name, c.Slug, imgData, contentType))
+		if err != nil {
+			c.logger().Warn("Could not look up an existing avatar", "name", name, "err", err)
+		} else if existing.URL != "" {
+			return existing, nil
+		}
+	}
+
+	var uploaded UploadedAsset
+	if sink != nil {
+		uploaded.URL, err = sink.Upload(ctx, imgData, contentType, name)
+	} else {
+		uploaded, err = c.uploadAsset(ctx, imgData, contentType, owner)
+	}
+	if err != nil {
+		return UploadedAsset{}, err
+	}
+
+	if c.VerifyAvatars {
+		if err := c.verifyAssetURL(ctx, uploaded.URL); err != nil {
+			return UploadedAsset{}, fmt.Errorf("verify asset: %w", err)
+		}
+	}
+
+	return uploaded, nil
+}
+
+// Upload stores data as a published Contentful asset and returns its CDN URL.
+// With DeferAssetPublish the asset is left for PublishAssetsBulk instead.
+// It implements assets.AssetUploader.
+func (c *Client) Upload(ctx context.Context, data []byte, contentType, name string) (string, error) {
+	uploaded, err := c.uploadAsset(ctx, data, contentType, AvatarOwner{Name: name})
+	return uploaded.URL, err
+}
 
-	uploadEndpoint := fmt.Sprintf("https://upload.contentful.com/spaces/%s/uploads", c.SpaceID)
-	uploadReq, err := http.NewRequestWithContext(ctx, "POST", uploadEndpoint, bytes.NewReader(imgData))
+// uploadAsset is Upload, returning the asset ID along with the URL and
+// describing the asset from owner.
+func (c *Client) uploadAsset(ctx context.Context, data []byte, contentType string, owner AvatarOwner) (UploadedAsset, error) {
+	name := owner.Name
+	fileName := avatarFileName(name, c.Slug, data, contentType)
+
+	uploadEndpoint := fmt.Sprintf("%s/spaces/%s/uploads", c.uploadBaseURL(), c.SpaceID)
+	uploadReq, err := http.NewRequestWithContext(ctx, "POST", uploadEndpoint, bytes.NewReader(data))
 	if err != nil {
-		return "", err
+		return UploadedAsset{}, err
 	}
 	uploadReq.Header.Set("Authorization", "Bearer "+c.Token)
 	uploadReq.Header.Set("Content-Type", "application/octet-stream")
 
-	uploadResp, err := c.HTTPClient.Do(uploadReq)
+	uploadResp, err := c.doWithRetry(uploadReq)
 	if err != nil {
-		return "", fmt.Errorf("upload binary: %w", err)
+		return UploadedAsset{}, fmt.Errorf("upload binary: %w", err)
 	}
 	defer uploadResp.Body.Close()
 
 	if uploadResp.StatusCode != 201 {
 		body, err := io.ReadAll(uploadResp.Body)
 		if err != nil {
-			return "", fmt.Errorf("upload failed (%d): could not read body: %w", uploadResp.StatusCode, err)
+			return UploadedAsset{}, fmt.Errorf("upload failed (%d): could not read body: %w", uploadResp.StatusCode, err)
 		}
-		return "", fmt.Errorf("upload failed (%d): %s", uploadResp.StatusCode, string(body))
+		return UploadedAsset{}, &httpx.StatusError{Op: "upload", StatusCode: uploadResp.StatusCode, Body: string(body)}
 	}
 
 	var uploadResult struct {
@@ -266,15 +643,16 @@ func (c *Client) UploadAvatar(ctx context.Context, imageURL, name string) (strin
 		} `json:"sys"`
 	}
 	if err := json.NewDecoder(uploadResp.Body).Decode(&uploadResult); err != nil {
-		return "", fmt.Errorf("decode upload: %w", err)
+		return UploadedAsset{}, fmt.Errorf("decode upload: %w", err)
 	}
 
-	assetEndpoint := fmt.Sprintf("%s/spaces/%s/environments/master/assets", servicekit.CMABaseURL, c.SpaceID)
+	assetEndpoint := fmt.Sprintf("%s/spaces/%s/environments/%s/assets", c.baseURL(), c.SpaceID, c.Environment)
 	assetBody := map[string]interface{}{
 		"fields": map[string]interface{}{
-			"title": map[string]interface{}{"en-US": name + " avatar"},
+			"title":       map[string]interface{}{c.Locale: owner.title()},
+			"description": map[string]interface{}{c.Locale: owner.description()},
 			"file": map[string]interface{}{
-				"en-US": map[string]interface{}{
+				c.Locale: map[string]interface{}{
 					"contentType": contentType,
 					"fileName":    fileName,
 					"uploadFrom": map[string]interface{}{
@@ -289,71 +667,96 @@ func (c *Client) UploadAvatar(ctx context.Context, imageURL, name string) (strin
 		},
 	}
 
+	if len(c.Tags) > 0 {
+		assetBody["metadata"] = tagMetadata(c.Tags)
+	}
+
 	assetBytes, err := json.Marshal(assetBody)
 	if err != nil {
-		return "", fmt.Errorf("marshal asset: %w", err)
+		return UploadedAsset{}, fmt.Errorf("marshal asset: %w", err)
 	}
 
 	assetReq, err := http.NewRequestWithContext(ctx, "POST", assetEndpoint, bytes.NewReader(assetBytes))
 	if err != nil {
-		return "", err
+		return UploadedAsset{}, err
 	}
 	assetReq.Header.Set("Authorization", "Bearer "+c.Token)
 	assetReq.Header.Set("Content-Type", "application/vnd.contentful.management.v1+json")
 
-	assetResp, err := c.HTTPClient.Do(assetReq)
+	assetResp, err := c.doWithRetry(assetReq)
 	if err != nil {
-		return "", fmt.Errorf("create asset: %w", err)
+		return UploadedAsset{}, fmt.Errorf("create asset: %w", err)
 	}
 	defer assetResp.Body.Close()
 
 	if assetResp.StatusCode != 201 {
 		body, err := io.ReadAll(assetResp.Body)
 		if err != nil {
-			return "", fmt.Errorf("create asset failed (%d): could not read body: %w", assetResp.StatusCode, err)
+			return UploadedAsset{}, fmt.Errorf("create asset failed (%d): could not read body: %w", assetResp.StatusCode, err)
 		}
-		return "", fmt.Errorf("create asset failed (%d): %s", assetResp.StatusCode, string(body))
+		return UploadedAsset{}, &httpx.StatusError{Op: "create asset", StatusCode: assetResp.StatusCode, Body: string(body)}
 	}
 
 	var assetResult servicekit.EntryItem
 	if err := json.NewDecoder(assetResp.Body).Decode(&assetResult); err != nil {
-		return "", fmt.Errorf("decode asset: %w", err)
+		return UploadedAsset{}, fmt.Errorf("decode asset: %w", err)
 	}
 
-	processEndpoint := fmt.Sprintf("%s/spaces/%s/environments/master/assets/%s/files/en-US/process",
-		servicekit.CMABaseURL, c.SpaceID, assetResult.Sys.ID)
+	processEndpoint := fmt.Sprintf("%s/spaces/%s/environments/%s/assets/%s/files/%s/process",
+		c.baseURL(), c.SpaceID, c.Environment, assetResult.Sys.ID, url.PathEscape(c.Locale))
 	processReq, err := http.NewRequestWithContext(ctx, "PUT", processEndpoint, nil)
 	if err != nil {
-		return "", err
+		return UploadedAsset{}, err
 	}
 	processReq.Header.Set("Authorization", "Bearer "+c.Token)
 	processReq.Header.Set("X-Contentful-Version", fmt.Sprintf("%d", assetResult.Sys.Version))
 
-	processResp, err := c.HTTPClient.Do(processReq)
+	processResp, err := c.doWithRetry(processReq)
 	if err != nil {
-		return "", fmt.Errorf("process asset: %w", err)
+		return UploadedAsset{}, fmt.Errorf("process asset: %w", err)
 	}
 	processResp.Body.Close()
 
 	if processResp.StatusCode != 204 {
-		return "", fmt.Errorf("process asset returned %d", processResp.StatusCode)
+		return UploadedAsset{}, fmt.Errorf("process asset returned %d", processResp.StatusCode)
 	}
 
-	assetGetEndpoint := fmt.Sprintf("%s/spaces/%s/environments/master/assets/%s",
-		servicekit.CMABaseURL, c.SpaceID, assetResult.Sys.ID)
+	assetGetEndpoint := fmt.Sprintf("%s/spaces/%s/environments/%s/assets/%s",
+		c.baseURL(), c.SpaceID, c.Environment, assetResult.Sys.ID)
+
+	interval, attempts := c.AssetPollInterval, c.AssetPollAttempts
+	if interval <= 0 {
+		interval = DefaultAssetPollInterval
+	}
+	if attempts <= 0 {
+		attempts = DefaultAssetPollAttempts
+	}
 
 	var cdnURL string
 	var assetVersion int
-	for i := 0; i < 20; i++ {
-		time.Sleep(500 * time.Millisecond)
+	delay := interval
+	for i := 0; i < attempts; i++ {
+		select {
+		case <-ctx.Done():
+			return UploadedAsset{}, fmt.Errorf("processing asset for %s: %w", name, ctx.Err())
+		case <-time.After(delay):
+		}
+		// Small images are usually ready within the first few polls; after
+		// that, wait a little longer each time, up to 4x the interval.
+		if i >= 2 {
+			delay = min(delay*5/4, 4*interval)
+		}
+		if err := ctx.Err(); err != nil {
+			return UploadedAsset{}, fmt.Errorf("processing asset for %s: %w", name, err)
+		}
 
 		getReq, err := http.NewRequestWithContext(ctx, "GET", assetGetEndpoint, nil)
 		if err != nil {
-			return "", err
+			return UploadedAsset{}, err
 		}
 		getReq.Header.Set("Authorization", "Bearer "+c.Token)
 
-		getResp, err := c.HTTPClient.Do(getReq)
+		getResp, err := c.doWithRetry(getReq)
 		if err != nil {
 			continue
 		}
@@ -370,8 +773,8 @@ func (c *Client) UploadAvatar(ctx context.Context, imageURL, name string) (strin
 
 		if fileField, ok := polled.Fields["file"]; ok {
 			if localeMap, ok := fileField.(map[string]interface{}); ok {
-				if enUS, ok := localeMap["en-US"].(map[string]interface{}); ok {
-					if u, ok := enUS["url"].(string); ok && u != "" {
+				if file, ok := localeMap[c.Locale].(map[string]interface{}); ok {
+					if u, ok := file["url"].(string); ok && u != "" {
 						cdnURL = "https:" + u
 						assetVersion = polled.Sys.Version
 						break
@@ -382,44 +785,127 @@ func (c *Client) UploadAvatar(ctx context.Context, imageURL, name string) (strin
 	}
 
 	if cdnURL == "" {
-		return "", fmt.Errorf("asset processing timed out for %s", name)
+		return UploadedAsset{}, fmt.Errorf("asset processing timed out for %s", name)
+	}
+
+	if c.DeferAssetPublish {
+		if c.pendingAssets == nil {
+			c.pendingAssets = make(map[string]int)
+		}
+		c.pendingAssets[assetResult.Sys.ID] = assetVersion
+		return UploadedAsset{URL: cdnURL, AssetID: assetResult.Sys.ID}, nil
 	}
 
 	if err := c.publishAsset(ctx, assetResult.Sys.ID, assetVersion); err != nil {
-		return "", fmt.Errorf("publish asset: %w", err)
+		return UploadedAsset{}, fmt.Errorf("publish asset: %w", err)
 	}
 
-	return cdnURL, nil
+	return UploadedAsset{URL: cdnURL, AssetID: assetResult.Sys.ID}, nil
+}
+
+// avatarFileName returns a stable file name for an avatar: the slugified
+// name plus a short hash of the image bytes, so the same image uploaded for
+// the same person always gets the same name.
+func avatarFileName(name string, slug assets.SlugOptions, data []byte, contentType string) string {
+	sum := sha256.Sum256(data)
+	return assets.Slugify(name, slug) + "-" + hex.EncodeToString(sum[:6]) + assets.ExtForContentType(contentType)
+}
+
+// findExistingAsset looks for a published asset whose file is named
+// fileName and returns it, or a zero UploadedAsset when there is none.
+func (c *Client) findExistingAsset(ctx context.Context, fileName string) (UploadedAsset, error) {
+	params := url.Values{}
+	params.Set("fields.file.fileName", fileName)
+	params.Set("sys.publishedAt[exists]", "true")
+	params.Set("limit", "1")
+	endpoint := fmt.Sprintf("%s/spaces/%s/environments/%s/assets?%s",
+		c.baseURL(), c.SpaceID, c.Environment, params.Encode())
+
+	var result struct {
+		Items []struct {
+			Sys    servicekit.EntrySys    `json:"sys"`
+			Fields map[string]interface{} `json:"fields"`
+		} `json:"items"`
+	}
+	if err := c.getJSON(ctx, endpoint, &result); err != nil {
+		return UploadedAsset{}, err
+	}
+	for _, item := range result.Items {
+		localeMap, ok := item.Fields["file"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if file, ok := localeMap[c.Locale].(map[string]interface{}); ok {
+			if u, ok := file["url"].(string); ok && u != "" {
+				return UploadedAsset{URL: "https:" + u, AssetID: item.Sys.ID}, nil
+			}
+		}
+	}
+	return UploadedAsset{}, nil
 }
 
-func slugify(name string) string {
-	s := strings.ToLower(strings.TrimSpace(name))
-	s = strings.ReplaceAll(s, " ", "-")
-	var b strings.Builder
-	for _, r := range s {
-		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
-			b.WriteRune(r)
+// verifyAssetURL issues HEAD requests against a published asset URL until it
+// returns 200, so callers never reference a URL the CDN can't serve yet.
+func (c *Client) verifyAssetURL(ctx context.Context, assetURL string) error {
+	lastStatus := 0
+	for i := 0; i < 10; i++ {
+		req, err := http.NewRequestWithContext(ctx, "HEAD", assetURL, nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := c.doWithRetry(req)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == 200 {
+				return nil
+			}
+			lastStatus = resp.StatusCode
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
 		}
 	}
-	return b.String()
+
+	return fmt.Errorf("%s not fetchable after publish (last status %d)", assetURL, lastStatus)
 }
 
-func extForContentType(ct string) string {
-	switch {
-	case strings.Contains(ct, "png"):
-		return ".png"
-	case strings.Contains(ct, "webp"):
-		return ".webp"
-	case strings.Contains(ct, "gif"):
-		return ".gif"
-	default:
-		return ".jpg"
+// PublishEntry publishes an entry in the client's environment. It shadows
+// the service kit method, which always targets master.
+func (c *Client) PublishEntry(ctx context.Context, entryID string, version int) error {
+	endpoint := fmt.Sprintf("%s/spaces/%s/environments/%s/entries/%s/published",
+		c.baseURL(), c.SpaceID, c.Environment, entryID)
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	req.Header.Set("X-Contentful-Version", fmt.Sprintf("%d", version))
+
+	resp, err := c.doWithRetry(req)
+	if err != nil {
+		return err
 	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("CMA publish failed (%d): could not read body: %w", resp.StatusCode, err)
+		}
+		return &httpx.StatusError{Op: "CMA publish", StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	return nil
 }
 
 func (c *Client) publishAsset(ctx context.Context, assetID string, version int) error {
-	endpoint := fmt.Sprintf("%s/spaces/%s/environments/master/assets/%s/published",
-		servicekit.CMABaseURL, c.SpaceID, assetID)
+	endpoint := fmt.Sprintf("%s/spaces/%s/environments/%s/assets/%s/published",
+		c.baseURL(), c.SpaceID, c.Environment, assetID)
 
 	req, err := http.NewRequestWithContext(ctx, "PUT", endpoint, nil)
 	if err != nil {
@@ -428,7 +914,7 @@ func (c *Client) publishAsset(ctx context.Context, assetID string, version int)
 	req.Header.Set("Authorization", "Bearer "+c.Token)
 	req.Header.Set("X-Contentful-Version", fmt.Sprintf("%d", version))
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.doWithRetry(req)
 	if err != nil {
 		return err
 	}
@@ -439,7 +925,7 @@ func (c *Client) publishAsset(ctx context.Context, assetID string, version int)
 		if err != nil {
 			return fmt.Errorf("CMA asset publish failed (%d): could not read body: %w", resp.StatusCode, err)
 		}
-		return fmt.Errorf("CMA asset publish failed (%d): %s", resp.StatusCode, string(body))
+		return &httpx.StatusError{Op: "CMA asset publish", StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	return nil
@@ -0,0 +1,397 @@
+package contentful
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// redirectTransport sends every request to target, keeping its path and
+// query, so tests can point the CMA client at an httptest server.
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (t redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newTestClient returns a Client whose requests are served by handler.
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	target, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewClient("space", "token")
+	c.HTTPClient = &http.Client{Transport: redirectTransport{target: target}}
+	return c
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func TestGetTestimonialsReadsConfiguredLocale(t *testing.T) {
+	entry := map[string]interface{}{
+		"sys": map[string]interface{}{"id": "entry1", "version": 3},
+		"fields": map[string]interface{}{
+			"sectionId": map[string]interface{}{"en-US": "testimonials"},
+			"content": map[string]interface{}{
+				"en-US": []interface{}{map[string]interface{}{"name": "Ana", "quote": "Great"}},
+				"de-DE": []interface{}{map[string]interface{}{"name": "Ana", "quote": "Toll"}},
+			},
+		},
+	}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"items": []interface{}{entry}})
+	})
+
+	tests := []struct {
+		locale          string
+		wantQuote       string
+		wantContent     string
+		wantSectionFrom string
+	}{
+		{locale: "en-US", wantQuote: "Great", wantContent: "en-US", wantSectionFrom: "en-US"},
+		{locale: "de-DE", wantQuote: "Toll", wantContent: "de-DE", wantSectionFrom: "en-US"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			c := newTestClient(t, handler)
+			c.Locale = tt.locale
+
+			result, err := c.GetTestimonials(context.Background())
+			if err != nil {
+				t.Fatalf("GetTestimonials() error = %v", err)
+			}
+			if len(result.Testimonials) != 1 || result.Testimonials[0].Quote != tt.wantQuote {
+				t.Errorf("Testimonials = %+v, want one with quote %q", result.Testimonials, tt.wantQuote)
+			}
+			if got := result.FieldLocales["content"]; got != tt.wantContent {
+				t.Errorf("FieldLocales[content] = %q, want %q", got, tt.wantContent)
+			}
+			if got := result.FieldLocales["sectionId"]; got != tt.wantSectionFrom {
+				t.Errorf("FieldLocales[sectionId] = %q, want fallback %q", got, tt.wantSectionFrom)
+			}
+		})
+	}
+}
+
+func TestUpdateTestimonialsSkipsUnchangedContent(t *testing.T) {
+	stored := []Testimonial{{Name: "Ana", Role: "Engineer", Quote: "Toll", Order: 1}}
+	entry := map[string]interface{}{
+		"sys": map[string]interface{}{"id": "entry1", "version": 7},
+		"fields": map[string]interface{}{
+			"content": map[string]interface{}{
+				"en-US": []interface{}{map[string]interface{}{"name": "Ana", "quote": "Great"}},
+				"de-DE": stored,
+			},
+		},
+	}
+	var puts int
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			writeJSON(w, http.StatusOK, map[string]interface{}{"items": []interface{}{entry}})
+		case "PUT":
+			puts++
+			writeJSON(w, http.StatusOK, map[string]interface{}{"sys": map[string]interface{}{"id": "entry1", "version": 8}})
+		default:
+			http.Error(w, "unexpected "+r.Method, http.StatusMethodNotAllowed)
+		}
+	})
+	c := newTestClient(t, handler)
+	c.Locale = "de-DE"
+
+	result, err := c.GetTestimonials(context.Background())
+	if err != nil {
+		t.Fatalf("GetTestimonials() error = %v", err)
+	}
+
+	version, err := c.UpdateTestimonials(context.Background(), result, []Testimonial{{Name: "Ana", Role: "Engineer", Quote: "Toll", Order: 1}})
+	if err != nil {
+		t.Fatalf("UpdateTestimonials() error = %v", err)
+	}
+	if puts != 0 {
+		t.Errorf("sent %d PUTs for unchanged content, want 0", puts)
+	}
+	if version != 7 {
+		t.Errorf("version = %d, want the current version 7", version)
+	}
+
+	version, err = c.UpdateTestimonials(context.Background(), result, []Testimonial{{Name: "Ana", Role: "Engineer", Quote: "Super", Order: 1}})
+	if err != nil {
+		t.Fatalf("UpdateTestimonials() error = %v", err)
+	}
+	if puts != 1 || version != 8 {
+		t.Errorf("changed content: sent %d PUTs and got version %d, want 1 PUT and version 8", puts, version)
+	}
+}
+
+func TestUpdateTestimonialsKeepsOtherLocales(t *testing.T) {
+	entry := map[string]interface{}{
+		"sys": map[string]interface{}{"id": "entry1", "version": 2},
+		"fields": map[string]interface{}{
+			"content": map[string]interface{}{
+				"en-US": []interface{}{map[string]interface{}{"name": "Ana", "quote": "Great"}},
+				"de-DE": []interface{}{map[string]interface{}{"name": "Ana", "quote": "Toll"}},
+			},
+		},
+	}
+	var put struct {
+		Fields struct {
+			Content map[string][]Testimonial `json:"content"`
+		} `json:"fields"`
+	}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			writeJSON(w, http.StatusOK, map[string]interface{}{"items": []interface{}{entry}})
+		case "PUT":
+			if err := json.NewDecoder(r.Body).Decode(&put); err != nil {
+				t.Errorf("decode PUT body: %v", err)
+			}
+			writeJSON(w, http.StatusOK, map[string]interface{}{"sys": map[string]interface{}{"id": "entry1", "version": 3}})
+		}
+	})
+	c := newTestClient(t, handler)
+	c.Locale = "de-DE"
+
+	result, err := c.GetTestimonials(context.Background())
+	if err != nil {
+		t.Fatalf("GetTestimonials() error = %v", err)
+	}
+	if _, err := c.UpdateTestimonials(context.Background(), result, []Testimonial{{Name: "Ana", Quote: "Super"}}); err != nil {
+		t.Fatalf("UpdateTestimonials() error = %v", err)
+	}
+
+	content := put.Fields.Content
+	if got := content["de-DE"]; !reflect.DeepEqual(got, []Testimonial{{Name: "Ana", Quote: "Super"}}) {
+		t.Errorf("de-DE content = %+v, want the new testimonials", got)
+	}
+	if got := content["en-US"]; !reflect.DeepEqual(got, []Testimonial{{Name: "Ana", Quote: "Great"}}) {
+		t.Errorf("en-US content = %+v, want it untouched", got)
+	}
+}
+
+func TestSectionRoundTrip(t *testing.T) {
+	// The fake CMA keeps created entries and answers reads filtered on
+	// fields.sectionId, like Contentful does.
+	var entries []map[string]interface{}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "POST":
+			var body map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("decode POST body: %v", err)
+			}
+			body["sys"] = map[string]interface{}{"id": "entry1", "version": 1}
+			entries = append(entries, body)
+			writeJSON(w, http.StatusCreated, body)
+		case "GET":
+			items := []interface{}{}
+			for _, e := range entries {
+				sectionID := e["fields"].(map[string]interface{})["sectionId"].(map[string]interface{})["en-US"]
+				if sectionID == r.URL.Query().Get("fields.sectionId") {
+					items = append(items, e)
+				}
+			}
+			writeJSON(w, http.StatusOK, map[string]interface{}{"items": items})
+		}
+	})
+	c := newTestClient(t, handler)
+	c.SectionID = "team-quotes"
+	c.SectionTitle = "Team quotes"
+
+	want := []Testimonial{{Name: "Ana", Quote: "Great"}}
+	if _, _, err := c.CreateTestimonials(context.Background(), want); err != nil {
+		t.Fatalf("CreateTestimonials() error = %v", err)
+	}
+	if got := entries[0]["fields"].(map[string]interface{})["title"]; !reflect.DeepEqual(got, map[string]interface{}{"en-US": "Team quotes"}) {
+		t.Errorf("title = %v, want the configured section title", got)
+	}
+
+	result, err := c.GetTestimonials(context.Background())
+	if err != nil {
+		t.Fatalf("GetTestimonials() error = %v", err)
+	}
+	if result.EntryID != "entry1" || !reflect.DeepEqual(result.Testimonials, want) {
+		t.Errorf("GetTestimonials() = entry %q with %+v, want entry1 with %+v", result.EntryID, result.Testimonials, want)
+	}
+
+	other := newTestClient(t, handler)
+	if result, err := other.GetTestimonials(context.Background()); err != nil || result.EntryID != "" {
+		t.Errorf("default section: GetTestimonials() = %+v, %v; want no entry", result, err)
+	}
+}
+
+func TestDecodeTestimonials(t *testing.T) {
+	want := []Testimonial{{Name: "Ana", Quote: "Great"}}
+	tests := []struct {
+		name         string
+		raw          interface{}
+		want         []Testimonial
+		wantIsString bool
+		wantErr      bool
+	}{
+		{
+			name: "array",
+			raw:  []interface{}{map[string]interface{}{"name": "Ana", "quote": "Great"}},
+			want: want,
+		},
+		{
+			name:         "JSON string",
+			raw:          `[{"name":"Ana","quote":"Great"}]`,
+			want:         want,
+			wantIsString: true,
+		},
+		{name: "empty string", raw: "  ", wantIsString: true},
+		{name: "string without an array", raw: "not json", wantIsString: true, wantErr: true},
+		{name: "object", raw: map[string]interface{}{"name": "Ana"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, isString, err := decodeTestimonials(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeTestimonials() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) || isString != tt.wantIsString {
+				t.Errorf("decodeTestimonials() = %+v, %t; want %+v, %t", got, isString, tt.want, tt.wantIsString)
+			}
+		})
+	}
+}
+
+func TestUpdateTestimonialsWritesStringContentBack(t *testing.T) {
+	entry := map[string]interface{}{
+		"sys": map[string]interface{}{"id": "entry1", "version": 5},
+		"fields": map[string]interface{}{
+			"content": map[string]interface{}{"en-US": `[{"name":"Ana","quote":"Great"}]`},
+		},
+	}
+	var put struct {
+		Fields struct {
+			Content map[string]interface{} `json:"content"`
+		} `json:"fields"`
+	}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			writeJSON(w, http.StatusOK, map[string]interface{}{"items": []interface{}{entry}})
+		case "PUT":
+			if err := json.NewDecoder(r.Body).Decode(&put); err != nil {
+				t.Errorf("decode PUT body: %v", err)
+			}
+			writeJSON(w, http.StatusOK, map[string]interface{}{"sys": map[string]interface{}{"id": "entry1", "version": 6}})
+		}
+	})
+	c := newTestClient(t, handler)
+
+	result, err := c.GetTestimonials(context.Background())
+	if err != nil {
+		t.Fatalf("GetTestimonials() error = %v", err)
+	}
+	if !result.ContentIsString {
+		t.Fatal("ContentIsString = false, want true for a string content field")
+	}
+	if _, err := c.UpdateTestimonials(context.Background(), result, []Testimonial{{Name: "Ana", Quote: "Super"}}); err != nil {
+		t.Fatalf("UpdateTestimonials() error = %v", err)
+	}
+
+	s, ok := put.Fields.Content["en-US"].(string)
+	if !ok {
+		t.Fatalf("written content = %#v, want a JSON string", put.Fields.Content["en-US"])
+	}
+	var got []Testimonial
+	if err := json.Unmarshal([]byte(s), &got); err != nil {
+		t.Fatalf("written content %q is not a JSON array: %v", s, err)
+	}
+	if want := []Testimonial{{Name: "Ana", Quote: "Super"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("written testimonials = %+v, want %+v", got, want)
+	}
+}
+
+// fakeCMA serves the image download and the CMA calls UploadAvatar makes,
+// recording the path of every process request.
+type fakeCMA struct {
+	locale string
+
+	mu           sync.Mutex
+	processPaths []string
+}
+
+func (f *fakeCMA) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.EscapedPath()
+	switch {
+	case r.Method == "GET" && path == "/avatar.jpg":
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write([]byte("not really a jpeg"))
+	case r.Method == "GET" && strings.HasSuffix(path, "/assets") && r.URL.Query().Has("fields.file.fileName"):
+		writeJSON(w, http.StatusOK, map[string]interface{}{"items": []interface{}{}})
+	case r.Method == "POST" && path == "/spaces/space/uploads":
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"sys": map[string]interface{}{"id": "upload1"}})
+	case r.Method == "POST" && path == "/spaces/space/environments/master/assets":
+		writeJSON(w, http.StatusCreated, map[string]interface{}{"sys": map[string]interface{}{"id": "asset1", "version": 1}})
+	case r.Method == "PUT" && strings.HasSuffix(path, "/process"):
+		f.mu.Lock()
+		f.processPaths = append(f.processPaths, path)
+		f.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == "GET" && path == "/spaces/space/environments/master/assets/asset1":
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"sys": map[string]interface{}{"id": "asset1", "version": 2},
+			"fields": map[string]interface{}{
+				"file": map[string]interface{}{
+					f.locale: map[string]interface{}{"url": "//images.ctfassets.net/avatar.jpg"},
+				},
+			},
+		})
+	default:
+		http.Error(w, "unexpected "+r.Method+" "+path, http.StatusNotFound)
+	}
+}
+
+func TestUploadAvatarProcessesConfiguredLocale(t *testing.T) {
+	for _, locale := range []string{"en-US", "de-DE", "es"} {
+		t.Run(locale, func(t *testing.T) {
+			fake := &fakeCMA{locale: locale}
+			srv := httptest.NewServer(fake)
+			defer srv.Close()
+
+			c := NewClient("space", "token")
+			c.BaseURL = srv.URL
+			c.UploadBaseURL = srv.URL
+			c.Locale = locale
+			c.AssetPollInterval = time.Millisecond
+			c.DeferAssetPublish = true
+
+			uploaded, err := c.UploadAvatar(context.Background(), srv.URL+"/avatar.jpg", AvatarOwner{Name: "Ana López"})
+			if err != nil {
+				t.Fatalf("UploadAvatar() error = %v", err)
+			}
+			if uploaded.URL != "https://images.ctfassets.net/avatar.jpg" {
+				t.Errorf("URL = %q, want the processed file's URL", uploaded.URL)
+			}
+
+			want := "/spaces/space/environments/master/assets/asset1/files/" + locale + "/process"
+			if len(fake.processPaths) != 1 || fake.processPaths[0] != want {
+				t.Errorf("process requests = %q, want [%q]", fake.processPaths, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package contentful
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/httpx"
+)
+
+// DefaultDeliveryBaseURL is the Content Delivery API host of spaces in the
+// default (US) region; EU-hosted spaces use EUDeliveryBaseURL.
+const (
+	DefaultDeliveryBaseURL = "https://cdn.contentful.com"
+	EUDeliveryBaseURL      = "https://cdn.eu.contentful.com"
+)
+
+// DeliveryClient reads testimonials through the Content Delivery API with
+// a read-only delivery token. It only sees published content.
+type DeliveryClient struct {
+	SpaceID string
+	Token   string
+
+	// SectionID, Locale, Environment and Model mean the same as on Client
+	// and have the same defaults.
+	SectionID   string
+	Locale      string
+	Environment string
+	Model       string
+
+	// BaseURL is the CDA host. Defaults to DefaultDeliveryBaseURL.
+	BaseURL string
+
+	HTTPClient *http.Client
+}
+
+// NewDeliveryClient creates a CDA client for the space. Requests use an
+// HTTP client with httpx.DefaultTimeout.
+func NewDeliveryClient(spaceID, token string) *DeliveryClient {
+	return &DeliveryClient{
+		SpaceID:     spaceID,
+		Token:       token,
+		SectionID:   DefaultSectionID,
+		Locale:      DefaultLocale,
+		Environment: DefaultEnvironment,
+		Model:       ModelEmbedded,
+		BaseURL:     DefaultDeliveryBaseURL,
+		HTTPClient:  httpx.NewClient(0),
+	}
+}
+
+// DeliveryBaseURLFor returns the CDA host paired with a CMA host:
+// api.<region>.contentful.com is served from cdn.<region>.contentful.com.
+// Hosts that don't follow that pattern get DefaultDeliveryBaseURL.
+func DeliveryBaseURLFor(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil || !strings.HasPrefix(u.Host, "api.") {
+		return DefaultDeliveryBaseURL
+	}
+	u.Host = "cdn." + strings.TrimPrefix(u.Host, "api.")
+	u.Path = ""
+	return u.String()
+}
+
+// deliveryEntry is an entry as the CDA returns it for a single locale:
+// fields hold plain values rather than locale maps.
+type deliveryEntry struct {
+	Sys struct {
+		ID       string `json:"id"`
+		Revision int    `json:"revision"`
+	} `json:"sys"`
+	Fields map[string]interface{} `json:"fields"`
+}
+
+type deliveryResponse struct {
+	Items    []deliveryEntry `json:"items"`
+	Includes struct {
+		Entry []deliveryEntry `json:"Entry"`
+	} `json:"includes"`
+}
+
+// GetTestimonials fetches the published testimonials siteSection entry.
+// The result has no RawFields, so it can't be passed to
+// Client.UpdateTestimonials; Version is the entry's published revision.
+func (d *DeliveryClient) GetTestimonials(ctx context.Context) (*TestimonialsResult, error) {
+	base := strings.TrimRight(d.BaseURL, "/")
+	if base == "" {
+		base = DefaultDeliveryBaseURL
+	}
+	params := url.Values{}
+	params.Set("content_type", "siteSection")
+	params.Set("fields.sectionId", d.SectionID)
+	params.Set("locale", d.Locale)
+	params.Set("limit", "1")
+	if d.Model == ModelReferences {
+		params.Set("include", "1")
+	}
+	endpoint := fmt.Sprintf("%s/spaces/%s/environments/%s/entries?%s", base, d.SpaceID, d.Environment, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+d.Token)
+
+	resp, err := d.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("CDA query failed (%d): could not read body: %w", resp.StatusCode, err)
+		}
+		return nil, &httpx.StatusError{Op: "CDA query", StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	var result deliveryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	if len(result.Items) == 0 {
+		return &TestimonialsResult{}, nil
+	}
+
+	entry := result.Items[0]
+	out := &TestimonialsResult{EntryID: entry.Sys.ID, Version: entry.Sys.Revision}
+	rawContent, ok := entry.Fields["content"]
+	if !ok {
+		return out, fmt.Errorf("entry has no 'content' field")
+	}
+
+	if d.Model == ModelReferences {
+		out.Testimonials, err = d.includedTestimonials(rawContent, result.Includes.Entry)
+		return out, err
+	}
+	out.Testimonials, out.ContentIsString, err = decodeTestimonials(rawContent)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// includedTestimonials resolves the entry links in a content field against
+// the entries the CDA included with the response. Links to unpublished or
+// deleted entries are not included and are skipped.
+func (d *DeliveryClient) includedTestimonials(rawContent interface{}, included []deliveryEntry) ([]Testimonial, error) {
+	data, err := json.Marshal(rawContent)
+	if err != nil {
+		return nil, fmt.Errorf("marshal content: %w", err)
+	}
+	var links []entryLink
+	if err := json.Unmarshal(data, &links); err != nil {
+		return nil, fmt.Errorf("content is not a list of entry links: %w", err)
+	}
+
+	byID := make(map[string]deliveryEntry, len(included))
+	for _, e := range included {
+		byID[e.Sys.ID] = e
+	}
+	var testimonials []Testimonial
+	for _, l := range links {
+		e, ok := byID[l.Sys.ID]
+		if !ok {
+			continue
+		}
+		t, err := testimonialFromFields(localeWrapped(e.Fields, d.Locale), d.Locale)
+		if err != nil {
+			return nil, fmt.Errorf("entry %s: %w", e.Sys.ID, err)
+		}
+		testimonials = append(testimonials, t)
+	}
+	return testimonials, nil
+}
+
+// localeWrapped wraps single-locale CDA fields in locale maps, the shape
+// the CMA returns, so CMA decoders can read them.
+func localeWrapped(fields map[string]interface{}, locale string) map[string]interface{} {
+	wrapped := make(map[string]interface{}, len(fields))
+	for name, v := range fields {
+		wrapped[name] = map[string]interface{}{locale: v}
+	}
+	return wrapped
+}
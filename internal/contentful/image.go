@@ -0,0 +1,164 @@
+package contentful
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+)
+
+// cropSquare center-crops an image to a square, re-encoding it in its
+// original format. Animated GIFs are cropped to a still of their first
+// frame. Formats without a standard-library encoder are returned
+// unchanged, as are images that are already square.
+func cropSquare(data []byte) ([]byte, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		// Unknown format (e.g. WebP): leave it alone.
+		return data, nil
+	}
+	if cfg.Width == cfg.Height {
+		return data, nil
+	}
+
+	var img image.Image
+	switch format {
+	case "jpeg", "png":
+		img, _, err = image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("decode %s: %w", format, err)
+		}
+	case "gif":
+		anim, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("decode gif: %w", err)
+		}
+		if len(anim.Image) == 0 {
+			return data, nil
+		}
+		// A frame may cover only part of the canvas, so draw it onto one.
+		frame := image.NewRGBA(image.Rect(0, 0, cfg.Width, cfg.Height))
+		draw.Draw(frame, anim.Image[0].Bounds(), anim.Image[0], anim.Image[0].Bounds().Min, draw.Src)
+		img = frame
+	default:
+		return data, nil
+	}
+
+	cropped := centerSquare(img)
+
+	var buf bytes.Buffer
+	switch format {
+	case "jpeg":
+		err = jpeg.Encode(&buf, cropped, &jpeg.Options{Quality: 90})
+	case "png":
+		err = png.Encode(&buf, cropped)
+	case "gif":
+		err = gif.Encode(&buf, cropped, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("encode %s: %w", format, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// centerSquare returns the largest centered square region of img.
+func centerSquare(img image.Image) image.Image {
+	b := img.Bounds()
+	side := min(b.Dx(), b.Dy())
+	x0 := b.Min.X + (b.Dx()-side)/2
+	y0 := b.Min.Y + (b.Dy()-side)/2
+	rect := image.Rect(x0, y0, x0+side, y0+side)
+
+	if sub, ok := img.(interface {
+		SubImage(r image.Rectangle) image.Image
+	}); ok {
+		return sub.SubImage(rect)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(dst, dst.Bounds(), img, rect.Min, draw.Src)
+	return dst
+}
+
+// downscale shrinks an image whose width or height exceeds maxDim so its
+// longer side is maxDim, keeping the aspect ratio, and re-encodes it as
+// JPEG, or PNG when it has transparency. It returns the new data and
+// content type. Images within the limit, animated GIFs and formats without
+// a standard-library decoder are returned unchanged with contentType.
+func downscale(data []byte, contentType string, maxDim int) ([]byte, string, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return data, contentType, nil
+	}
+	if cfg.Width <= maxDim && cfg.Height <= maxDim {
+		return data, contentType, nil
+	}
+	if format == "gif" {
+		anim, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			return nil, "", fmt.Errorf("decode gif: %w", err)
+		}
+		if len(anim.Image) > 1 {
+			return data, contentType, nil
+		}
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("decode %s: %w", format, err)
+	}
+	w, h := cfg.Width, cfg.Height
+	if w >= h {
+		w, h = maxDim, max(1, h*maxDim/w)
+	} else {
+		w, h = max(1, w*maxDim/h), maxDim
+	}
+	resized := resizeArea(img, w, h)
+
+	var buf bytes.Buffer
+	if o, ok := img.(interface{ Opaque() bool }); ok && !o.Opaque() {
+		if err := png.Encode(&buf, resized); err != nil {
+			return nil, "", fmt.Errorf("encode png: %w", err)
+		}
+		return buf.Bytes(), "image/png", nil
+	}
+	if err := jpeg.Encode(&buf, resized, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, "", fmt.Errorf("encode jpeg: %w", err)
+	}
+	return buf.Bytes(), "image/jpeg", nil
+}
+
+// resizeArea scales img down to w x h by averaging the source pixels each
+// destination pixel covers. It is only meant for shrinking.
+func resizeArea(img image.Image, w, h int) *image.NRGBA {
+	b := img.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		y0 := b.Min.Y + y*b.Dy()/h
+		y1 := max(b.Min.Y+(y+1)*b.Dy()/h, y0+1)
+		for x := 0; x < w; x++ {
+			x0 := b.Min.X + x*b.Dx()/w
+			x1 := max(b.Min.X+(x+1)*b.Dx()/w, x0+1)
+			// Sum premultiplied values so transparent pixels don't bleed.
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r += uint64(cr)
+					g += uint64(cg)
+					bl += uint64(cb)
+					a += uint64(ca)
+					n++
+				}
+			}
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / n), G: uint16(g / n), B: uint16(bl / n), A: uint16(a / n),
+			})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,119 @@
+package contentful
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// fixture returns a w×h image filled with a solid color.
+func fixture(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: 200, G: 80, B: 40, A: 255})
+		}
+	}
+	return img
+}
+
+func encodeFixture(t *testing.T, format string, w, h int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "jpeg":
+		err = jpeg.Encode(&buf, fixture(w, h), nil)
+	case "png":
+		err = png.Encode(&buf, fixture(w, h))
+	case "gif":
+		err = gif.Encode(&buf, fixture(w, h), nil)
+	}
+	if err != nil {
+		t.Fatalf("encode %s fixture: %v", format, err)
+	}
+	return buf.Bytes()
+}
+
+// animatedGIF returns a two-frame w×h GIF whose first frame covers only
+// the canvas's left half.
+func animatedGIF(t *testing.T, w, h int) []byte {
+	t.Helper()
+	first := image.NewPaletted(image.Rect(0, 0, w/2, h), palette.Plan9)
+	second := image.NewPaletted(image.Rect(0, 0, w, h), palette.Plan9)
+	anim := &gif.GIF{
+		Image:  []*image.Paletted{first, second},
+		Delay:  []int{10, 10},
+		Config: image.Config{ColorModel: color.Palette(palette.Plan9), Width: w, Height: h},
+	}
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, anim); err != nil {
+		t.Fatalf("encode animated gif fixture: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestCropSquare(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     func(t *testing.T) []byte
+		wantSide int
+		format   string
+	}{
+		{"landscape jpeg", func(t *testing.T) []byte { return encodeFixture(t, "jpeg", 300, 200) }, 200, "jpeg"},
+		{"portrait jpeg", func(t *testing.T) []byte { return encodeFixture(t, "jpeg", 120, 250) }, 120, "jpeg"},
+		{"landscape png", func(t *testing.T) []byte { return encodeFixture(t, "png", 301, 100) }, 100, "png"},
+		{"portrait png", func(t *testing.T) []byte { return encodeFixture(t, "png", 64, 65) }, 64, "png"},
+		{"still gif", func(t *testing.T) []byte { return encodeFixture(t, "gif", 90, 60) }, 60, "gif"},
+		{"animated gif", func(t *testing.T) []byte { return animatedGIF(t, 80, 50) }, 50, "gif"},
+		{"square png", func(t *testing.T) []byte { return encodeFixture(t, "png", 40, 40) }, 40, "png"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := cropSquare(tt.data(t))
+			if err != nil {
+				t.Fatalf("cropSquare() error = %v", err)
+			}
+			cfg, format, err := image.DecodeConfig(bytes.NewReader(out))
+			if err != nil {
+				t.Fatalf("decode output: %v", err)
+			}
+			if format != tt.format {
+				t.Errorf("output format = %s, want %s", format, tt.format)
+			}
+			if cfg.Width != tt.wantSide || cfg.Height != tt.wantSide {
+				t.Errorf("output is %dx%d, want %dx%d", cfg.Width, cfg.Height, tt.wantSide, tt.wantSide)
+			}
+		})
+	}
+}
+
+func TestCropSquareAnimatedGIFKeepsFirstFrameOnly(t *testing.T) {
+	out, err := cropSquare(animatedGIF(t, 80, 50))
+	if err != nil {
+		t.Fatalf("cropSquare() error = %v", err)
+	}
+	anim, err := gif.DecodeAll(bytes.NewReader(out))
+	if err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	if len(anim.Image) != 1 {
+		t.Errorf("output has %d frames, want 1", len(anim.Image))
+	}
+}
+
+func TestCropSquareLeavesUnknownFormats(t *testing.T) {
+	data := []byte("RIFF\x00\x00\x00\x00WEBPVP8 not really")
+	out, err := cropSquare(data)
+	if err != nil {
+		t.Fatalf("cropSquare() error = %v", err)
+	}
+	if !bytes.Equal(out, data) {
+		t.Error("cropSquare() changed data in an unknown format")
+	}
+}
@@ -0,0 +1,102 @@
+package contentful
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/httpx"
+)
+
+// ErrInvalidToken is returned by VerifyAccess when Contentful rejects the
+// CMA token itself.
+var ErrInvalidToken = errors.New("invalid or expired CMA token")
+
+// ErrNoWriteAccess is returned by CheckWriteAccess when the token can read
+// the space but may not write entries.
+var ErrNoWriteAccess = errors.New("CMA token cannot write to this space/environment")
+
+// VerifyAccess makes one cheap request, reading the space, to confirm the
+// token is accepted and can see the space before any expensive work.
+func (c *Client) VerifyAccess(ctx context.Context) error {
+	endpoint := fmt.Sprintf("%s/spaces/%s", c.baseURL(), c.SpaceID)
+	var space struct{}
+	err := c.getJSON(ctx, endpoint, &space)
+	var statusErr *httpx.StatusError
+	if !errors.As(err, &statusErr) {
+		return err
+	}
+	switch statusErr.StatusCode {
+	case http.StatusUnauthorized:
+		return ErrInvalidToken
+	case http.StatusNotFound:
+		return fmt.Errorf("space %q not found for this token", c.SpaceID)
+	}
+	return err
+}
+
+// CheckWriteAccess verifies that the token can reach the configured space
+// and environment and write the testimonials entry, without changing it.
+//
+// The write probe is a PUT with version 0, which Contentful always rejects
+// as a version conflict (409) once permissions have passed; a read-only
+// token gets 403 instead. The probe carries the entry's current fields, so
+// even a PUT that were accepted would leave the content as it is. When the
+// entry doesn't exist yet only the environment is checked.
+func (c *Client) CheckWriteAccess(ctx context.Context) error {
+	envEndpoint := fmt.Sprintf("%s/spaces/%s/environments/%s",
+		c.baseURL(), c.SpaceID, c.Environment)
+	var env struct{}
+	if err := c.getJSON(ctx, envEndpoint, &env); err != nil {
+		var statusErr *httpx.StatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+			return fmt.Errorf("space %q or environment %q not found for this token", c.SpaceID, c.Environment)
+		}
+		return fmt.Errorf("read environment: %w", err)
+	}
+
+	result, err := c.GetTestimonials(ctx)
+	if err != nil {
+		return fmt.Errorf("read testimonials: %w", err)
+	}
+	if result.EntryID == "" {
+		return nil
+	}
+
+	endpoint := fmt.Sprintf("%s/spaces/%s/environments/%s/entries/%s",
+		c.baseURL(), c.SpaceID, c.Environment, result.EntryID)
+	body, err := json.Marshal(map[string]interface{}{"fields": result.RawFields})
+	if err != nil {
+		return fmt.Errorf("marshal probe: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "PUT", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	req.Header.Set("Content-Type", "application/vnd.contentful.management.v1+json")
+	req.Header.Set("X-Contentful-Version", "0")
+
+	resp, err := c.doWithRetry(req)
+	if err != nil {
+		return fmt.Errorf("write probe: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusConflict:
+		return nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrNoWriteAccess
+	default:
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("write probe failed (%d): could not read body: %w", resp.StatusCode, err)
+		}
+		return &httpx.StatusError{Op: "write probe", StatusCode: resp.StatusCode, Body: string(body)}
+	}
+}
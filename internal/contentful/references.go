@@ -0,0 +1,277 @@
+package contentful
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+
+	servicekit "github.com/alberto-moreno-sa/go-service-kit/contentful"
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/httpx"
+)
+
+// Content models for the siteSection content field.
+const (
+	// ModelEmbedded stores testimonials as a JSON array inside the field.
+	ModelEmbedded = "embedded"
+	// ModelReferences stores a list of links to TestimonialContentType
+	// entries, which other sections may share.
+	ModelReferences = "references"
+)
+
+// TestimonialContentType is the content type of referenced testimonial
+// entries in ModelReferences.
+const TestimonialContentType = "testimonial"
+
+// entryLink is a Contentful link to an entry.
+type entryLink struct {
+	Sys struct {
+		Type     string `json:"type"`
+		LinkType string `json:"linkType"`
+		ID       string `json:"id"`
+	} `json:"sys"`
+}
+
+func newEntryLink(id string) entryLink {
+	var l entryLink
+	l.Sys.Type = "Link"
+	l.Sys.LinkType = "Entry"
+	l.Sys.ID = id
+	return l
+}
+
+// resolveLinkedEntries follows the entry links in a content field and
+// returns the referenced testimonials in link order. Links to entries that
+// no longer exist are skipped.
+func (c *Client) resolveLinkedEntries(ctx context.Context, rawContent interface{}) ([]Testimonial, []LinkedEntry, error) {
+	data, err := json.Marshal(rawContent)
+	if err != nil {
+		return nil, nil, fmt.Errorf("marshal content: %w", err)
+	}
+	var links []entryLink
+	if err := json.Unmarshal(data, &links); err != nil {
+		return nil, nil, fmt.Errorf("content is not a list of entry links: %w", err)
+	}
+
+	byID := make(map[string]servicekit.EntryItem, len(links))
+	const batch = 100
+	for start := 0; start < len(links); start += batch {
+		ids := make([]string, 0, batch)
+		for _, l := range links[start:min(start+batch, len(links))] {
+			ids = append(ids, l.Sys.ID)
+		}
+		params := url.Values{}
+		params.Set("sys.id[in]", strings.Join(ids, ","))
+		params.Set("limit", fmt.Sprint(batch))
+		endpoint := fmt.Sprintf("%s/spaces/%s/environments/%s/entries?%s",
+			c.baseURL(), c.SpaceID, c.Environment, params.Encode())
+
+		var resp servicekit.EntriesResponse
+		if err := c.getJSON(ctx, endpoint, &resp); err != nil {
+			return nil, nil, fmt.Errorf("fetch linked entries: %w", err)
+		}
+		for _, item := range resp.Items {
+			byID[item.Sys.ID] = item
+		}
+	}
+
+	var testimonials []Testimonial
+	var linked []LinkedEntry
+	for _, l := range links {
+		item, ok := byID[l.Sys.ID]
+		if !ok {
+			continue
+		}
+		t, err := testimonialFromFields(item.Fields, c.Locale)
+		if err != nil {
+			return nil, nil, fmt.Errorf("entry %s: %w", item.Sys.ID, err)
+		}
+		testimonials = append(testimonials, t)
+		linked = append(linked, LinkedEntry{
+			ID:          item.Sys.ID,
+			Version:     item.Sys.Version,
+			RawFields:   item.Fields,
+			Testimonial: t,
+		})
+	}
+	return testimonials, linked, nil
+}
+
+// writeReferences makes sure every testimonial has an entry, reusing (and
+// if needed updating) the linked entry with the same name and company, and
+// returns the links in testimonial order. Created and updated entries are
+// published unless c.DraftReferences is set.
+func (c *Client) writeReferences(ctx context.Context, linked []LinkedEntry, testimonials []Testimonial) ([]entryLink, error) {
+	byKey := make(map[string]LinkedEntry, len(linked))
+	for _, le := range linked {
+		byKey[refKey(le.Testimonial)] = le
+	}
+
+	links := make([]entryLink, 0, len(testimonials))
+	for _, t := range testimonials {
+		key := refKey(t)
+		le, ok := byKey[key]
+		delete(byKey, key)
+
+		id := le.ID
+		switch {
+		case ok && reflect.DeepEqual(le.Testimonial, t):
+		case ok:
+			version, err := c.putTestimonialEntry(ctx, le, t)
+			if err != nil {
+				return nil, fmt.Errorf("update testimonial %s: %w", t.Name, err)
+			}
+			if err := c.publishReference(ctx, t, id, version); err != nil {
+				return nil, err
+			}
+		default:
+			var version int
+			var err error
+			id, version, err = c.createTestimonialEntry(ctx, t)
+			if err != nil {
+				return nil, fmt.Errorf("create testimonial %s: %w", t.Name, err)
+			}
+			if err := c.publishReference(ctx, t, id, version); err != nil {
+				return nil, err
+			}
+		}
+		links = append(links, newEntryLink(id))
+	}
+	return links, nil
+}
+
+// publishReference publishes a written testimonial entry, or with
+// c.DraftReferences leaves it as a draft and logs its ID.
+func (c *Client) publishReference(ctx context.Context, t Testimonial, id string, version int) error {
+	if c.DraftReferences {
+		c.logger().Info("Left testimonial entry unpublished for review", "name", t.Name, "entry", id)
+		return nil
+	}
+	if err := c.PublishEntry(ctx, id, version); err != nil {
+		return fmt.Errorf("publish testimonial %s: %w", t.Name, err)
+	}
+	return nil
+}
+
+func (c *Client) createTestimonialEntry(ctx context.Context, t Testimonial) (string, int, error) {
+	fields, err := testimonialFields(nil, c.Locale, t)
+	if err != nil {
+		return "", 0, err
+	}
+	endpoint := fmt.Sprintf("%s/spaces/%s/environments/%s/entries", c.baseURL(), c.SpaceID, c.Environment)
+	created, err := c.writeEntry(ctx, "POST", endpoint, fields, map[string]string{
+		"X-Contentful-Content-Type": TestimonialContentType,
+	})
+	if err != nil {
+		return "", 0, err
+	}
+	return created.Sys.ID, created.Sys.Version, nil
+}
+
+func (c *Client) putTestimonialEntry(ctx context.Context, le LinkedEntry, t Testimonial) (int, error) {
+	fields, err := testimonialFields(le.RawFields, c.Locale, t)
+	if err != nil {
+		return 0, err
+	}
+	endpoint := fmt.Sprintf("%s/spaces/%s/environments/%s/entries/%s",
+		c.baseURL(), c.SpaceID, c.Environment, le.ID)
+	updated, err := c.writeEntry(ctx, "PUT", endpoint, fields, map[string]string{
+		"X-Contentful-Version": fmt.Sprint(le.Version),
+	})
+	if err != nil {
+		return 0, err
+	}
+	return updated.Sys.Version, nil
+}
+
+// writeEntry sends an entry create or update with the given fields.
+func (c *Client) writeEntry(ctx context.Context, method, endpoint string, fields map[string]interface{}, headers map[string]string) (*servicekit.EntryItem, error) {
+	bodyBytes, err := json.Marshal(map[string]interface{}{"fields": fields})
+	if err != nil {
+		return nil, fmt.Errorf("marshal body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	req.Header.Set("Content-Type", "application/vnd.contentful.management.v1+json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.doWithRetry(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 && resp.StatusCode != 201 {
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("CMA entry write failed (%d): could not read body: %w", resp.StatusCode, err)
+		}
+		return nil, &httpx.StatusError{Op: "CMA entry write", StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	var item servicekit.EntryItem
+	if err := json.NewDecoder(resp.Body).Decode(&item); err != nil {
+		return nil, fmt.Errorf("decode entry: %w", err)
+	}
+	return &item, nil
+}
+
+// testimonialFields converts t into locale-wrapped entry fields, keeping
+// any other fields already on the entry.
+func testimonialFields(raw map[string]interface{}, locale string, t Testimonial) (map[string]interface{}, error) {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return nil, fmt.Errorf("marshal testimonial: %w", err)
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("unmarshal testimonial: %w", err)
+	}
+
+	fields := make(map[string]interface{}, len(raw)+len(values))
+	for k, v := range raw {
+		fields[k] = v
+	}
+	for k, v := range values {
+		fields[k] = withLocale(raw[k], locale, v)
+	}
+	return fields, nil
+}
+
+// testimonialFromFields reads a testimonial from locale-wrapped entry fields.
+func testimonialFromFields(fields map[string]interface{}, locale string) (Testimonial, error) {
+	values := make(map[string]interface{}, len(fields))
+	for name, field := range fields {
+		if localeMap, ok := field.(map[string]interface{}); ok {
+			if v, _, ok := pickLocale(localeMap, locale); ok {
+				values[name] = v
+			}
+		}
+	}
+
+	var t Testimonial
+	data, err := json.Marshal(values)
+	if err != nil {
+		return t, fmt.Errorf("marshal fields: %w", err)
+	}
+	if err := json.Unmarshal(data, &t); err != nil {
+		return t, fmt.Errorf("unmarshal testimonial: %w", err)
+	}
+	return t, nil
+}
+
+// refKey matches a testimonial to its entry by normalized name and company.
+func refKey(t Testimonial) string {
+	return strings.ToLower(strings.TrimSpace(t.Name)) + "|" + strings.ToLower(strings.TrimSpace(t.Company))
+}
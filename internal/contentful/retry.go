@@ -0,0 +1,75 @@
+package contentful
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultMaxRetries is how many times a rate-limited or failed CMA
+	// request is retried before its response is returned as is.
+	DefaultMaxRetries = 3
+
+	// retryBackoffBase and retryBackoffMax bound the exponential pause used
+	// when the CMA doesn't say how long to wait.
+	retryBackoffBase = 500 * time.Millisecond
+	retryBackoffMax  = 30 * time.Second
+)
+
+// doWithRetry sends req, retrying up to c.MaxRetries times on 429 and 5xx
+// responses. It waits for X-Contentful-RateLimit-Reset when the response
+// carries it and otherwise backs off exponentially with jitter. The final
+// response is returned unchanged, so callers handle errors as before.
+func (c *Client) doWithRetry(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil || !retryableStatus(resp.StatusCode) || attempt >= c.MaxRetries {
+			return resp, err
+		}
+		if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			// The body can't be replayed.
+			return resp, nil
+		}
+
+		delay := retryDelay(resp.Header, attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return resp, nil
+		}
+		resp.Body.Close()
+		c.logger().Warn("CMA request failed; retrying", "method", req.Method, "path", req.URL.Path, "status", resp.StatusCode, "delay", delay.Round(time.Millisecond))
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("rewind request body: %w", err)
+			}
+			req.Body = body
+		}
+	}
+}
+
+// retryableStatus reports whether a CMA response is worth retrying.
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= 500
+}
+
+// retryDelay returns how long to wait before retry attempt+1: the seconds
+// in X-Contentful-RateLimit-Reset when present, otherwise an exponential
+// backoff capped at retryBackoffMax with up to 50% jitter added.
+func retryDelay(h http.Header, attempt int) time.Duration {
+	if secs, err := strconv.Atoi(h.Get("X-Contentful-RateLimit-Reset")); err == nil && secs >= 0 {
+		return time.Duration(secs)*time.Second + time.Duration(rand.Int64N(int64(retryBackoffBase)))
+	}
+	delay := min(retryBackoffBase<<attempt, retryBackoffMax)
+	return delay + time.Duration(rand.Int64N(int64(delay/2)+1))
+}
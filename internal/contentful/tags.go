@@ -0,0 +1,54 @@
+package contentful
+
+import (
+	"slices"
+
+	servicekit "github.com/alberto-moreno-sa/go-service-kit/contentful"
+)
+
+// entriesResponse is servicekit.EntriesResponse with each entry's metadata,
+// which carries its tags.
+type entriesResponse struct {
+	Items []entryItem `json:"items"`
+	Total int         `json:"total"`
+}
+
+type entryItem struct {
+	servicekit.EntryItem
+	Metadata struct {
+		Tags []struct {
+			Sys struct {
+				ID string `json:"id"`
+			} `json:"sys"`
+		} `json:"tags"`
+	} `json:"metadata"`
+}
+
+// tagIDs returns the IDs of the tags on e.
+func (e entryItem) tagIDs() []string {
+	var ids []string
+	for _, t := range e.Metadata.Tags {
+		ids = append(ids, t.Sys.ID)
+	}
+	return ids
+}
+
+// tagMetadata returns a metadata block linking each distinct tag in ids.
+func tagMetadata(ids []string) map[string]interface{} {
+	var links []interface{}
+	var seen []string
+	for _, id := range ids {
+		if id == "" || slices.Contains(seen, id) {
+			continue
+		}
+		seen = append(seen, id)
+		links = append(links, map[string]interface{}{
+			"sys": map[string]interface{}{
+				"type":     "Link",
+				"linkType": "Tag",
+				"id":       id,
+			},
+		})
+	}
+	return map[string]interface{}{"tags": links}
+}
@@ -1,13 +1,34 @@
 package contentful
 
+import "strings"
+
 // Testimonial matches the JSON structure in the Contentful siteSection content field.
 type Testimonial struct {
 	Name        string `json:"name"`
+	FirstName   string `json:"firstName,omitempty"`
+	LastName    string `json:"lastName,omitempty"`
 	Role        string `json:"role"`
 	Company     string `json:"company"`
 	Quote       string `json:"quote"`
 	AvatarURL   string `json:"avatarUrl,omitempty"`
 	LinkedInURL string `json:"linkedInUrl,omitempty"`
+	QuoteLang   string `json:"quoteLang,omitempty"`
+	Pronouns    string `json:"pronouns,omitempty"`
+	// CreatedAt (RFC 3339) is when the recommendation was written, and
+	// Relationship how the two people worked together, e.g. "managed
+	// directly". Either may be empty.
+	CreatedAt    string `json:"createdAt,omitempty"`
+	Relationship string `json:"relationship,omitempty"`
+	// MutualConnections is the recommender's mutual connection count, when
+	// LinkedIn exposes it.
+	MutualConnections *int `json:"mutualConnections,omitempty"`
+	// SourceProfile, ScrapedAt (RFC 3339) and ToolVersion record where and
+	// when this entry was last scraped, and by which build of this tool.
+	SourceProfile string `json:"sourceProfile,omitempty"`
+	ScrapedAt     string `json:"scrapedAt,omitempty"`
+	ToolVersion   string `json:"toolVersion,omitempty"`
+	// Order lets editors curate the display order. Zero means unordered.
+	Order int `json:"order,omitempty"`
 }
 
 // TestimonialsResult holds the fetched testimonials along with entry metadata
@@ -17,4 +38,88 @@ type TestimonialsResult struct {
 	EntryID      string
 	Version      int
 	RawFields    map[string]interface{}
+	// FieldLocales records, per locale-wrapped field, which locale key the
+	// value was read from.
+	FieldLocales map[string]string
+	// LinkedEntries holds the referenced testimonial entries, parallel to
+	// Testimonials, when the client uses ModelReferences.
+	LinkedEntries []LinkedEntry
+	// TagIDs are the IDs of the tags on the entry.
+	TagIDs []string
+	// ContentIsString reports that the content field holds the testimonials
+	// as a JSON-encoded string, e.g. in a Text field, rather than as an
+	// array. UpdateTestimonials writes them back the same way.
+	ContentIsString bool
+}
+
+// LinkedEntry is a testimonial entry referenced from the section's content
+// field, with the metadata needed to update it in place.
+type LinkedEntry struct {
+	ID          string
+	Version     int
+	RawFields   map[string]interface{}
+	Testimonial Testimonial
+}
+
+// BuildLogEntry is a single execution record in the shared build log. It
+// mirrors the service kit's entry and adds fields specific to this tool.
+type BuildLogEntry struct {
+	Service string `json:"service"`
+	// SectionID is the siteSection the run synced. Entries written before
+	// it was recorded have none.
+	SectionID       string `json:"sectionId,omitempty"`
+	Timestamp       string `json:"timestamp"`
+	TriggeredBy     string `json:"triggeredBy"`
+	ForceUpdate     bool   `json:"forceUpdate"`
+	TranslationUsed bool   `json:"translationUsed"`
+	NewAdded        int    `json:"newAdded"`
+	TotalAfterSync  int    `json:"totalAfterSync"`
+	Status          string `json:"status"`
+	ContentHash     string `json:"contentHash,omitempty"`
+}
+
+// BuildLogResult holds the fetched build log along with entry metadata
+// needed for the fetch-mutate-put update pattern.
+type BuildLogResult struct {
+	Entries   []BuildLogEntry
+	EntryID   string
+	Version   int
+	RawFields map[string]interface{}
+}
+
+// UploadedAsset is an avatar stored by UploadAvatar.
+type UploadedAsset struct {
+	// URL is the public URL the image is served from.
+	URL string
+	// AssetID is the Contentful asset's sys.id. It is empty when an
+	// AvatarSink stored the image outside Contentful.
+	AssetID string
+}
+
+// AvatarOwner is the person an avatar shows. Role and Company are optional
+// and only used to describe the asset.
+type AvatarOwner struct {
+	Name    string
+	Role    string
+	Company string
+}
+
+// title is the asset title, searchable in the media library.
+func (o AvatarOwner) title() string {
+	return "Profile photo of " + o.Name
+}
+
+// description is the asset's alt text, e.g. "Jane Doe, CTO at Acme". It is
+// empty when neither role nor company is known.
+func (o AvatarOwner) description() string {
+	switch {
+	case o.Role != "" && o.Company != "" && !strings.Contains(strings.ToLower(o.Role), strings.ToLower(o.Company)):
+		return o.Name + ", " + o.Role + " at " + o.Company
+	case o.Role != "":
+		return o.Name + ", " + o.Role
+	case o.Company != "":
+		return o.Name + ", " + o.Company
+	default:
+		return ""
+	}
 }
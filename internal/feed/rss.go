@@ -0,0 +1,100 @@
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/contentful"
+)
+
+// Channel describes the feed itself.
+type Channel struct {
+	Title       string
+	Link        string
+	Description string
+}
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title       string    `xml:"title"`
+	Link        string    `xml:"link"`
+	Description string    `xml:"description"`
+	Items       []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link,omitempty"`
+	Description string   `xml:"description"`
+	PubDate     string   `xml:"pubDate,omitempty"`
+	GUID        *rssGUID `xml:"guid,omitempty"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+// WriteRSS renders testimonials as an RSS 2.0 document, newest first by
+// CreatedAt. Testimonials without a date follow the dated ones, in reverse
+// of their stored order since new testimonials are appended.
+func WriteRSS(w io.Writer, ch Channel, testimonials []contentful.Testimonial) error {
+	doc := rss{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:       ch.Title,
+			Link:        ch.Link,
+			Description: ch.Description,
+		},
+	}
+
+	items := slices.Clone(testimonials)
+	slices.Reverse(items)
+	slices.SortStableFunc(items, func(a, b contentful.Testimonial) int {
+		return createdAt(b).Compare(createdAt(a))
+	})
+
+	for _, t := range items {
+		item := rssItem{
+			Title:       "Recommendation from " + t.Name,
+			Link:        t.LinkedInURL,
+			Description: t.Quote,
+		}
+		if created := createdAt(t); !created.IsZero() {
+			item.PubDate = created.Format(time.RFC1123Z)
+		}
+		if t.LinkedInURL != "" {
+			item.GUID = &rssGUID{Value: t.LinkedInURL, IsPermaLink: true}
+		}
+		doc.Channel.Items = append(doc.Channel.Items, item)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("encode rss: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// createdAt parses t.CreatedAt, returning the zero time when it is missing
+// or malformed.
+func createdAt(t contentful.Testimonial) time.Time {
+	created, err := time.Parse(time.RFC3339, t.CreatedAt)
+	if err != nil {
+		return time.Time{}
+	}
+	return created
+}
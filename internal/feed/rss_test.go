@@ -0,0 +1,91 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/contentful"
+)
+
+func TestWriteRSS(t *testing.T) {
+	testimonials := []contentful.Testimonial{
+		{Name: "Ana", Quote: "Great & kind", LinkedInURL: "https://www.linkedin.com/in/ana", CreatedAt: "2023-05-01T10:00:00Z"},
+		{Name: "Bo", Quote: "Sharp"},
+		{Name: "Cy", Quote: "Reliable", LinkedInURL: "https://www.linkedin.com/in/cy", CreatedAt: "2024-02-03T08:30:00Z"},
+	}
+
+	var buf bytes.Buffer
+	err := WriteRSS(&buf, Channel{Title: "Testimonials", Link: "https://example.com", Description: "Recommendations"}, testimonials)
+	if err != nil {
+		t.Fatalf("WriteRSS() error = %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), xml.Header) {
+		t.Errorf("output does not start with the XML header:\n%s", buf.String())
+	}
+
+	var doc rss
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("output is not valid XML: %v\n%s", err, buf.String())
+	}
+	if doc.Version != "2.0" {
+		t.Errorf("version = %q, want 2.0", doc.Version)
+	}
+	wantChannel := rssChannel{Title: "Testimonials", Link: "https://example.com", Description: "Recommendations"}
+	gotChannel := doc.Channel
+	gotChannel.Items = nil
+	if !reflect.DeepEqual(gotChannel, wantChannel) {
+		t.Errorf("channel = %+v, want %+v", gotChannel, wantChannel)
+	}
+
+	want := []rssItem{
+		{
+			Title:       "Recommendation from Cy",
+			Link:        "https://www.linkedin.com/in/cy",
+			Description: "Reliable",
+			PubDate:     "Sat, 03 Feb 2024 08:30:00 +0000",
+			GUID:        &rssGUID{Value: "https://www.linkedin.com/in/cy", IsPermaLink: true},
+		},
+		{
+			Title:       "Recommendation from Ana",
+			Link:        "https://www.linkedin.com/in/ana",
+			Description: "Great & kind",
+			PubDate:     "Mon, 01 May 2023 10:00:00 +0000",
+			GUID:        &rssGUID{Value: "https://www.linkedin.com/in/ana", IsPermaLink: true},
+		},
+		{Title: "Recommendation from Bo", Description: "Sharp"},
+	}
+	if !reflect.DeepEqual(doc.Channel.Items, want) {
+		t.Errorf("items = %+v, want %+v", doc.Channel.Items, want)
+	}
+}
+
+func TestWriteRSSKeepsUndatedNewestFirst(t *testing.T) {
+	testimonials := []contentful.Testimonial{
+		{Name: "Ana", Quote: "First"},
+		{Name: "Bo", Quote: "Second"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteRSS(&buf, Channel{Title: "Testimonials"}, testimonials); err != nil {
+		t.Fatalf("WriteRSS() error = %v", err)
+	}
+	var doc rss
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("output is not valid XML: %v", err)
+	}
+
+	var got []string
+	for _, item := range doc.Channel.Items {
+		got = append(got, item.Title)
+	}
+	want := []string{"Recommendation from Bo", "Recommendation from Ana"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("items = %v, want the last stored first: %v", got, want)
+	}
+	if strings.Contains(buf.String(), "<pubDate>") {
+		t.Errorf("undated testimonials got a pubDate:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,37 @@
+package httpx
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DefaultTimeout bounds a whole request, including dialing and reading the body.
+const DefaultTimeout = 30 * time.Second
+
+// NewClient returns an HTTP client with the tool's standard settings. A zero
+// timeout uses DefaultTimeout.
+func NewClient(timeout time.Duration) *http.Client {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &http.Client{Timeout: timeout}
+}
+
+// StatusError reports an unexpected HTTP status, keeping the code available
+// so callers can tell transient failures from permanent ones.
+type StatusError struct {
+	// Op names the failed call, e.g. "CMA update".
+	Op         string
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s failed (%d): %s", e.Op, e.StatusCode, e.Body)
+}
+
+// Temporary reports whether the status is worth retrying: 429 or any 5xx.
+func (e *StatusError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
@@ -0,0 +1,36 @@
+package linkedin
+
+import gosync "sync"
+
+// urnCache remembers lookups by URN for the length of a Scrape, so a
+// recommender who appears more than once is only fetched once. Concurrent
+// callers asking for the same URN wait for the first one's result.
+type urnCache[T any] struct {
+	mu      gosync.Mutex
+	entries map[string]*urnEntry[T]
+}
+
+type urnEntry[T any] struct {
+	once gosync.Once
+	val  T
+	err  error
+}
+
+// get returns the cached result for urn, calling fetch the first time.
+func (c *urnCache[T]) get(urn string, fetch func() (T, error)) (T, error) {
+	c.mu.Lock()
+	if c.entries == nil {
+		c.entries = make(map[string]*urnEntry[T])
+	}
+	e, ok := c.entries[urn]
+	if !ok {
+		e = &urnEntry[T]{}
+		c.entries[urn] = e
+	}
+	c.mu.Unlock()
+
+	e.once.Do(func() {
+		e.val, e.err = fetch()
+	})
+	return e.val, e.err
+}
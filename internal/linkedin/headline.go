@@ -0,0 +1,102 @@
+package linkedin
+
+import "strings"
+
+// headlineConnectors join a role to a company in headlines, in the
+// languages LinkedIn members commonly write them in. They only ever mean
+// "at", so the earliest one wins.
+var headlineConnectors = []string{
+	" at ",     // English
+	" @ ",      // any
+	" chez ",   // French
+	" bei ",    // German
+	" presso ", // Italian
+	" bij ",    // Dutch
+	" na ",     // Portuguese
+	" em ",     // Portuguese
+}
+
+// weakHeadlineConnectors also appear inside roles ("Ingeniero en software
+// en Acme", "Ingénieur en logiciel chez Acme"). They are only used when no
+// headlineConnector matches, and the last one wins.
+var weakHeadlineConnectors = []string{
+	" en ", // Spanish, French
+}
+
+// headlineSeparators end the company part of a headline, e.g. "Engineer at
+// Acme | Speaker".
+var headlineSeparators = []string{"|", "·", "•", " - ", " – ", ";"}
+
+// companySuffixes are legal-form suffixes that may follow a comma without
+// ending the company, as in "Acme, Inc.".
+var companySuffixes = []string{
+	"inc", "llc", "llp", "ltd", "limited", "corp", "co",
+	"gmbh", "ag", "sa", "s.a", "sl", "s.l", "srl", "s.r.l", "sas", "bv", "b.v", "nv", "plc",
+}
+
+// companyFromHeadline extracts the company from a "Role at Company" style
+// headline, or returns "" when no connector is found. The company ends at
+// the first separator, or at a comma not followed by a legal suffix.
+func companyFromHeadline(headline string) string {
+	lower := strings.ToLower(headline)
+	if len(lower) != len(headline) {
+		// Offsets into lower wouldn't line up with headline.
+		lower = headline
+	}
+	at, start := -1, 0
+	for _, c := range headlineConnectors {
+		if i := strings.Index(lower, c); i >= 0 && (at < 0 || i < at) {
+			at, start = i, i+len(c)
+		}
+	}
+	if at < 0 {
+		for _, c := range weakHeadlineConnectors {
+			if i := strings.LastIndex(lower, c); i > at {
+				at, start = i, i+len(c)
+			}
+		}
+	}
+	if at < 0 {
+		return ""
+	}
+
+	company := headline[start:]
+	for _, sep := range headlineSeparators {
+		if i := strings.Index(company, sep); i >= 0 {
+			company = company[:i]
+		}
+	}
+	return strings.TrimSpace(cutAtComma(company))
+}
+
+// cutAtComma cuts company at the first comma that isn't followed by a
+// companySuffix.
+func cutAtComma(company string) string {
+	offset := 0
+	for {
+		i := strings.Index(company[offset:], ",")
+		if i < 0 {
+			return company
+		}
+		i += offset
+		if !hasCompanySuffix(company[i+1:]) {
+			return company[:i]
+		}
+		offset = i + 1
+	}
+}
+
+// hasCompanySuffix reports whether s starts with a companySuffix word.
+func hasCompanySuffix(s string) bool {
+	word := strings.TrimSpace(s)
+	if end := strings.IndexAny(word, " ,"); end >= 0 {
+		word = word[:end]
+	}
+	word = strings.TrimSuffix(strings.ToLower(word), ".")
+	for _, suffix := range companySuffixes {
+		if word == suffix {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package linkedin
+
+import "testing"
+
+func TestCompanyFromHeadline(t *testing.T) {
+	tests := []struct {
+		headline string
+		want     string
+	}{
+		{"Software Engineer at Acme", "Acme"},
+		{"Software Engineer @ Acme", "Acme"},
+		{"Senior Engineer AT Acme", "Acme"},
+		{"Ingeniero de software en Acme", "Acme"},
+		{"Ingeniero en software en Acme", "Acme"},
+		{"Ingénieur en logiciel chez Acme", "Acme"},
+		{"Ingenieur bei Acme GmbH", "Acme GmbH"},
+		{"Ingegnere presso Acme", "Acme"},
+		{"Engenheiro de software na Acme", "Acme"},
+		{"Engenheiro de software em Acme", "Acme"},
+		{"Ontwikkelaar bij Acme", "Acme"},
+		{"Engineer at Acme | Speaker", "Acme"},
+		{"Engineer at Acme · Author", "Acme"},
+		{"Engineer at Acme - Remote", "Acme"},
+		{"Engineer at Acme; Mentor", "Acme"},
+		{"Engineer at Acme, Speaker", "Acme"},
+		{"Engineer at Acme, Inc.", "Acme, Inc."},
+		{"Engineer at Acme, Inc. | Speaker", "Acme, Inc."},
+		{"Ingeniero en Acme, S.L.", "Acme, S.L."},
+		{"Engineer at Acme, LLC, Speaker", "Acme, LLC"},
+		{"Software Engineer", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.headline, func(t *testing.T) {
+			if got := companyFromHeadline(tt.headline); got != tt.want {
+				t.Errorf("companyFromHeadline(%q) = %q, want %q", tt.headline, got, tt.want)
+			}
+		})
+	}
+}
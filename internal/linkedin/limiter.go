@@ -0,0 +1,37 @@
+package linkedin
+
+import (
+	"context"
+	"sync"
+)
+
+// Limiter is a request budget and rate shared by every Scrape it is passed
+// to, so the per-run limits hold when several profiles are scraped at once.
+type Limiter struct {
+	maxRequests int
+	// throttle spaces out requests; nil means no limit.
+	throttle *throttle
+
+	mu       sync.Mutex
+	requests int
+}
+
+// NewLimiter returns a Limiter allowing maxRequests Voyager requests in
+// total, at most perSecond per second. Zero means unlimited for either.
+func NewLimiter(maxRequests int, perSecond float64) *Limiter {
+	return &Limiter{maxRequests: maxRequests, throttle: newThrottle(perSecond)}
+}
+
+// take counts one request against the budget, returning
+// ErrRequestBudgetExceeded once it is used up, then waits for the request's
+// turn under the rate limit.
+func (l *Limiter) take(ctx context.Context) error {
+	l.mu.Lock()
+	if l.maxRequests > 0 && l.requests >= l.maxRequests {
+		l.mu.Unlock()
+		return ErrRequestBudgetExceeded
+	}
+	l.requests++
+	l.mu.Unlock()
+	return l.throttle.wait(ctx)
+}
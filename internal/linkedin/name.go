@@ -0,0 +1,48 @@
+package linkedin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NameFormat controls how a recommender's display name is built.
+type NameFormat string
+
+const (
+	// NameFull is "First Last", the default.
+	NameFull NameFormat = "full"
+	// NameFirst is the first name only.
+	NameFirst NameFormat = "first"
+	// NameLastFirst is "Last, First".
+	NameLastFirst NameFormat = "last-first"
+)
+
+// ParseNameFormat validates a --name-format value. Empty means NameFull.
+func ParseNameFormat(s string) (NameFormat, error) {
+	switch f := NameFormat(s); f {
+	case "":
+		return NameFull, nil
+	case NameFull, NameFirst, NameLastFirst:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown name format %q (want full, first or last-first)", s)
+	}
+}
+
+// FormatName builds a display name from first and last names. Formats that
+// need a missing part fall back to whatever is available.
+func FormatName(first, last string, format NameFormat) string {
+	first, last = strings.TrimSpace(first), strings.TrimSpace(last)
+	switch format {
+	case NameFirst:
+		if first != "" {
+			return first
+		}
+		return last
+	case NameLastFirst:
+		if first != "" && last != "" {
+			return last + ", " + first
+		}
+	}
+	return strings.TrimSpace(first + " " + last)
+}
@@ -0,0 +1,55 @@
+package linkedin
+
+import "testing"
+
+func TestParseNameFormat(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    NameFormat
+		wantErr bool
+	}{
+		{in: "", want: NameFull},
+		{in: "full", want: NameFull},
+		{in: "first", want: NameFirst},
+		{in: "last-first", want: NameLastFirst},
+		{in: "Full", wantErr: true},
+		{in: "last", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseNameFormat(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseNameFormat(%q) error = %v, wantErr %t", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseNameFormat(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFormatName(t *testing.T) {
+	tests := []struct {
+		name        string
+		first, last string
+		format      NameFormat
+		want        string
+	}{
+		{name: "full", first: "Ana", last: "López", format: NameFull, want: "Ana López"},
+		{name: "full trims", first: " Ana ", last: " López ", format: NameFull, want: "Ana López"},
+		{name: "full without last", first: "Ana", format: NameFull, want: "Ana"},
+		{name: "first", first: "Ana", last: "López", format: NameFirst, want: "Ana"},
+		{name: "first falls back to last", last: "López", format: NameFirst, want: "López"},
+		{name: "last-first", first: "Ana", last: "López", format: NameLastFirst, want: "López, Ana"},
+		{name: "last-first without last", first: "Ana", format: NameLastFirst, want: "Ana"},
+		{name: "last-first without first", last: "López", format: NameLastFirst, want: "López"},
+		{name: "empty format is full", first: "Ana", last: "López", want: "Ana López"},
+		{name: "both empty", format: NameLastFirst, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatName(tt.first, tt.last, tt.format); got != tt.want {
+				t.Errorf("FormatName(%q, %q, %q) = %q, want %q", tt.first, tt.last, tt.format, got, tt.want)
+			}
+		})
+	}
+}
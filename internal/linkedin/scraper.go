@@ -1,29 +1,167 @@
 package linkedin
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
 	"strings"
+	gosync "sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/httpx"
 )
 
+// Defaults for Options.BaseURL and Options.ProtocolVersion.
+const (
+	DefaultVoyagerBaseURL  = "https://www.linkedin.com/voyager/api"
+	DefaultProtocolVersion = "2.0.0"
+)
+
+// DefaultAvatarWidth is the avatar width picked when Options.AvatarWidth
+// is zero.
+const DefaultAvatarWidth = 200
+
 const (
-	voyagerBaseURL    = "https://www.linkedin.com/voyager/api"
-	userAgent         = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) " +
+	userAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) " +
 		"AppleWebKit/537.36 (KHTML, like Gecko) Chrome/145.0.0.0 Safari/537.36"
 	profileDecoration = "com.linkedin.voyager.dash.deco.identity.profile.TopCardSupplementary-166"
+	emptyRetryDelay   = time.Second
+	// maxThrottleRetries bounds how often a single request answered with
+	// 429 is resent, starting throttleBackoffBase apart.
+	maxThrottleRetries  = 3
+	throttleBackoffBase = 2 * time.Second
+	// profileProjection is the Rest.li field selector sent with fetchProfile
+	// so Voyager returns only the fields dashProfile decodes.
+	profileProjection = "firstName,lastName,headline,publicIdentifier,profilePicture,pronoun,customPronoun"
 )
 
+// ErrRequestBudgetExceeded is returned once a scrape has used up its
+// Options.MaxRequests allowance.
+var ErrRequestBudgetExceeded = errors.New("linkedin request budget exceeded")
+
+// ErrCookieExpired is returned when LinkedIn rejects the li_at session:
+// an auth status, its 999 bot challenge, or a login page instead of JSON.
+var ErrCookieExpired = errors.New("linkedin session cookie expired or rejected")
+
+// ErrRateLimited is returned when Voyager answers 429 Too Many Requests.
+var ErrRateLimited = errors.New("linkedin rate limited")
+
+// Options tunes a Scrape run. The zero value is valid.
+type Options struct {
+	// Verbose logs each resolved URN, Voyager request and response
+	// status, and how many recommendations were returned and kept, at
+	// debug level. Otherwise only warnings are logged.
+	Verbose bool
+	// MaxRequests caps the number of Voyager API calls a run may make.
+	// Zero means unlimited.
+	MaxRequests int
+	// Rate caps Voyager requests per second. Zero means unlimited.
+	Rate float64
+	// Limiter, when set, replaces MaxRequests and Rate with a budget and
+	// rate shared with other Scrape calls given the same Limiter.
+	Limiter *Limiter
+	// EnrichConcurrency is how many recommenders are looked up in
+	// parallel. Values below 1 mean one at a time.
+	EnrichConcurrency int
+	// NoEnrich skips the per-recommender profile and company lookups.
+	// Returned recommendations then carry only the quote.
+	NoEnrich bool
+	// PrintURNs logs every URN involved and each endpoint requested, so
+	// lookups can be replayed by hand.
+	PrintURNs bool
+	// AvatarWidth is the smallest avatar width wanted, in pixels. The
+	// narrowest artifact at least this wide is used, or the widest one when
+	// none is. Zero means DefaultAvatarWidth.
+	AvatarWidth int
+	// NameFormat selects how Recommendation.Name is built. Empty means NameFull.
+	NameFormat NameFormat
+	// BaseURL and ProtocolVersion override DefaultVoyagerBaseURL and
+	// DefaultProtocolVersion, for when LinkedIn moves the API.
+	BaseURL         string
+	ProtocolVersion string
+	// Logger receives progress at debug and info level and lookup
+	// failures as warnings. Nil discards them.
+	Logger *slog.Logger
+}
+
 // voyagerClient wraps the HTTP client and CSRF token for Voyager API calls.
 type voyagerClient struct {
-	httpClient *http.Client
-	liAtCookie string
-	csrfToken  string
+	httpClient      *http.Client
+	liAtCookie      string
+	csrfToken       string
+	limiter         *Limiter
+	printURNs       bool
+	verbose         bool
+	logger          *slog.Logger
+	baseURL         string
+	protocolVersion string
+	// noProjection is set once Voyager rejects profileProjection, so later
+	// profile fetches go straight to the full payload.
+	noProjection bool
+
+	// mu guards noProjection, which enrichment workers share.
+	mu gosync.Mutex
+
+	// profiles and cards cache enrichment lookups by recommender URN.
+	profiles urnCache[*dashProfile]
+	cards    urnCache[topCard]
+
+	// failedLookups counts recommender lookups that failed or were skipped
+	// once the request budget ran out.
+	failedLookups atomic.Int64
+}
+
+// do sends a Voyager request, enforcing the limiter's request budget and
+// rate. A 429 response is retried up to maxThrottleRetries times with a
+// doubling pause; the last response is returned as is. 999 is not retried:
+// it is LinkedIn's bot challenge, which the caller reports as
+// ErrCookieExpired.
+func (vc *voyagerClient) do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	delay := throttleBackoffBase
+	for attempt := 0; ; attempt++ {
+		if err := vc.limiter.take(ctx); err != nil {
+			return nil, err
+		}
+		if vc.printURNs {
+			vc.logger.Info("[urns] request", "method", req.Method, "url", req.URL.String())
+		}
+		start := time.Now()
+		resp, err := vc.httpClient.Do(req)
+		if err == nil {
+			vc.trace("Voyager request", "method", req.Method, "path", req.URL.Path,
+				"status", resp.StatusCode, "elapsed", time.Since(start).Round(time.Millisecond))
+		}
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests || attempt >= maxThrottleRetries {
+			return resp, err
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return resp, nil
+		}
+		resp.Body.Close()
+		vc.logger.Warn("LinkedIn throttled request; retrying", "status", resp.StatusCode, "path", req.URL.Path, "delay", delay)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// trace logs a debug line when Options.Verbose is set.
+func (vc *voyagerClient) trace(msg string, args ...any) {
+	if vc.verbose {
+		vc.logger.Debug(msg, args...)
+	}
 }
 
 func (vc *voyagerClient) newRequest(ctx context.Context, method, url string) (*http.Request, error) {
@@ -33,46 +171,216 @@ func (vc *voyagerClient) newRequest(ctx context.Context, method, url string) (*h
 	}
 	req.Header.Set("User-Agent", userAgent)
 	req.Header.Set("csrf-token", vc.csrfToken)
-	req.Header.Set("x-restli-protocol-version", "2.0.0")
+	req.Header.Set("x-restli-protocol-version", vc.protocolVersion)
 	req.AddCookie(&http.Cookie{Name: "li_at", Value: vc.liAtCookie})
 	req.AddCookie(&http.Cookie{Name: "JSESSIONID", Value: vc.csrfToken})
 	return req, nil
 }
 
 // Scrape fetches LinkedIn recommendations for the given profile using the Voyager API.
-func Scrape(ctx context.Context, username string, liAtCookie string, verbose bool) ([]Recommendation, error) {
+// username is the profile's public identifier or URL; when empty the
+// logged-in user's own recommendations are fetched.
+//
+// failedLookups counts recommender profile and company lookups that failed
+// or were cut off by the request budget. Those recommendations are
+// returned incomplete or not at all, so a non-zero count means the result
+// may be missing recommendations that are still on LinkedIn.
+func Scrape(ctx context.Context, username string, liAtCookie string, opts Options) (recs []Recommendation, failedLookups int, err error) {
 	client := &http.Client{}
 
 	// Step 1: Get JSESSIONID (CSRF token) by visiting LinkedIn
 	csrfToken, err := fetchCSRFToken(ctx, client, liAtCookie)
 	if err != nil {
-		return nil, fmt.Errorf("csrf token: %w", err)
+		return nil, 0, fmt.Errorf("csrf token: %w", err)
 	}
 
 	vc := &voyagerClient{
-		httpClient: client,
-		liAtCookie: liAtCookie,
-		csrfToken:  csrfToken,
+		httpClient:      client,
+		liAtCookie:      liAtCookie,
+		csrfToken:       csrfToken,
+		limiter:         opts.Limiter,
+		printURNs:       opts.PrintURNs,
+		verbose:         opts.Verbose,
+		logger:          opts.Logger,
+		baseURL:         opts.BaseURL,
+		protocolVersion: opts.ProtocolVersion,
+	}
+	if vc.limiter == nil {
+		vc.limiter = NewLimiter(opts.MaxRequests, opts.Rate)
+	}
+	if vc.baseURL == "" {
+		vc.baseURL = DefaultVoyagerBaseURL
+	}
+	if vc.protocolVersion == "" {
+		vc.protocolVersion = DefaultProtocolVersion
+	}
+	if vc.logger == nil {
+		vc.logger = slog.New(slog.DiscardHandler)
+	}
+
+	// Step 2: Resolve the profile URN from the public identifier, or via
+	// /me when no username is given
+	profileURN, err := vc.resolveProfileURN(ctx, username)
+	if err != nil {
+		return nil, 0, fmt.Errorf("profile URN: %w", err)
+	}
+	vc.trace("Resolved profile URN", "username", username, "urn", profileURN)
+
+	// Step 3: Fetch recommendations via dash API. LinkedIn occasionally
+	// answers the first call with an empty list from a cold cache, so an
+	// empty response is retried once.
+	elements, err := vc.fetchRecommendations(ctx, profileURN)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(elements) == 0 {
+		vc.trace("Recommendations response was empty; retrying once")
+		select {
+		case <-ctx.Done():
+			return nil, 0, ctx.Err()
+		case <-time.After(emptyRetryDelay):
+		}
+		elements, err = vc.fetchRecommendations(ctx, profileURN)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	vc.trace("Fetched recommendations", "elements", len(elements))
+
+	// Step 4: Enrich each recommendation with recommender profile data,
+	// using up to opts.EnrichConcurrency workers. Results keep the order
+	// LinkedIn returned them in.
+	if opts.NoEnrich {
+		for _, elem := range elements {
+			if elem.RecommendationText != "" {
+				recs = append(recs, newRecommendation(elem))
+			}
+		}
+		vc.trace("Kept recommendations with text", "kept", len(recs), "dropped", len(elements)-len(recs))
+		return recs, 0, nil
+	}
+
+	enriched := make([]*Recommendation, len(elements))
+	var budgetReached atomic.Bool
+	jobs := make(chan int)
+	var wg gosync.WaitGroup
+	for w := 0; w < max(opts.EnrichConcurrency, 1); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if budgetReached.Load() {
+					vc.failedLookups.Add(1)
+					continue
+				}
+				rec, err := vc.enrich(ctx, elements[i], opts)
+				// A budget error after the profile lookup still leaves
+				// a usable rec, only without the company details.
+				if rec.Name != "" && rec.Quote != "" {
+					enriched[i] = &rec
+				}
+				if errors.Is(err, ErrRequestBudgetExceeded) {
+					budgetReached.Store(true)
+					vc.failedLookups.Add(1)
+				}
+			}
+		}()
+	}
+	for i, elem := range elements {
+		if elem.RecommendationText != "" {
+			jobs <- i
+		}
 	}
+	close(jobs)
+	wg.Wait()
 
-	// Step 2: Resolve profile URN via /me
-	profileURN, err := vc.fetchProfileURN(ctx)
+	for _, rec := range enriched {
+		if rec != nil {
+			recs = append(recs, *rec)
+		}
+	}
+	vc.trace("Kept enriched recommendations", "kept", len(recs), "dropped", len(elements)-len(recs))
+	if budgetReached.Load() {
+		vc.logger.Warn("Request budget reached; stopping enrichment", "budget", vc.limiter.maxRequests, "recommendations", len(recs))
+	}
+	return recs, int(vc.failedLookups.Load()), nil
+}
+
+// enrich builds a Recommendation from elem, looking up the recommender's
+// profile and company. Lookup failures are logged and leave the fields
+// empty; only ErrRequestBudgetExceeded is returned, along with whatever
+// was filled in before the budget ran out.
+func (vc *voyagerClient) enrich(ctx context.Context, elem dashRecommendation, opts Options) (Recommendation, error) {
+	rec := newRecommendation(elem)
+	if elem.RecommenderProfileURN == "" {
+		return rec, nil
+	}
+	vc.trace("Enriching recommendation", "recommender", elem.RecommenderProfileURN)
+
+	if opts.PrintURNs {
+		vc.logger.Info("[urns] recommender", "urn", elem.RecommenderProfileURN)
+	}
+
+	// Fetch recommender's profile details
+	profile, err := vc.profiles.get(elem.RecommenderProfileURN, func() (*dashProfile, error) {
+		return vc.fetchProfile(ctx, elem.RecommenderProfileURN)
+	})
+	if errors.Is(err, ErrRequestBudgetExceeded) {
+		return rec, err
+	}
+	if err != nil {
+		vc.failedLookups.Add(1)
+		vc.logger.Warn("Could not fetch recommender profile", "urn", elem.RecommenderProfileURN, "err", err)
+	} else {
+		rec.FirstName = strings.TrimSpace(profile.FirstName)
+		rec.LastName = strings.TrimSpace(profile.LastName)
+		rec.Name = FormatName(rec.FirstName, rec.LastName, opts.NameFormat)
+		rec.Role = profile.Headline
+		if profile.PublicIdentifier != "" {
+			rec.LinkedInURL = "https://www.linkedin.com/in/" + profile.PublicIdentifier
+		}
+		var width int
+		rec.AvatarURL, width = extractAvatarURL(profile.ProfilePicture, cmp.Or(opts.AvatarWidth, DefaultAvatarWidth))
+		if rec.AvatarURL != "" {
+			vc.trace("Picked avatar", "name", rec.Name, "width", width)
+		}
+		rec.Pronouns = profile.pronouns()
+	}
+
+	// Fetch company separately (requires decoration)
+	card, err := vc.cards.get(elem.RecommenderProfileURN, func() (topCard, error) {
+		return vc.fetchCompanyByURN(ctx, elem.RecommenderProfileURN)
+	})
+	if errors.Is(err, ErrRequestBudgetExceeded) {
+		// Keep the profile details; the company comes from the headline.
+		rec.Company = companyFromHeadline(rec.Role)
+		return rec, err
+	}
 	if err != nil {
-		return nil, fmt.Errorf("profile URN: %w", err)
+		vc.failedLookups.Add(1)
+		vc.logger.Warn("Could not fetch recommender company", "name", rec.Name, "err", err)
+	} else {
+		rec.Company = card.Company
+		rec.MutualConnections = card.MutualConnections
 	}
-	log.Printf("Resolved profile URN: %s", profileURN)
+	if rec.Company == "" {
+		rec.Company = companyFromHeadline(rec.Role)
+	}
+	return rec, nil
+}
 
-	// Step 3: Fetch recommendations via dash API
+// fetchRecommendations fetches the visible recommendations received by profileURN.
+func (vc *voyagerClient) fetchRecommendations(ctx context.Context, profileURN string) ([]dashRecommendation, error) {
 	encodedURN := url.QueryEscape(profileURN)
 	endpoint := fmt.Sprintf("%s/identity/dash/recommendations?q=received&profileUrn=%s&recommendationStatuses=List(VISIBLE)",
-		voyagerBaseURL, encodedURN)
+		vc.baseURL, encodedURN)
 
 	req, err := vc.newRequest(ctx, "GET", endpoint)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := client.Do(req)
+	resp, err := vc.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("voyager request: %w", err)
 	}
@@ -83,7 +391,11 @@ func Scrape(ctx context.Context, username string, liAtCookie string, verbose boo
 		if err != nil {
 			return nil, fmt.Errorf("voyager API returned %d: could not read body: %w", resp.StatusCode, err)
 		}
-		return nil, fmt.Errorf("voyager API returned %d: %s", resp.StatusCode, string(body))
+		if sessionRejected(resp, body) {
+			return nil, fmt.Errorf("voyager API returned %d: %w", resp.StatusCode, ErrCookieExpired)
+		}
+		vc.protocolHint(resp.StatusCode, body)
+		return nil, &httpx.StatusError{Op: "voyager API", StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	var result dashRecommendationsResponse
@@ -91,56 +403,75 @@ func Scrape(ctx context.Context, username string, liAtCookie string, verbose boo
 		return nil, fmt.Errorf("decode voyager response: %w", err)
 	}
 
-	// Step 4: Enrich each recommendation with recommender profile data
-	var recs []Recommendation
-	for _, elem := range result.Elements {
-		if elem.RecommendationText == "" {
-			continue
-		}
+	return result.Elements, nil
+}
 
-		rec := Recommendation{
-			Quote: elem.RecommendationText,
-		}
+// resolveProfileURN returns the profile URN for username, a public
+// identifier or profile URL, looking it up with the memberIdentity finder.
+// An empty username resolves to the logged-in user via /me.
+func (vc *voyagerClient) resolveProfileURN(ctx context.Context, username string) (string, error) {
+	username = strings.TrimSpace(username)
+	if slug := SlugFromURL(username); slug != "" {
+		username = slug
+	}
+	if username == "" {
+		return vc.fetchProfileURN(ctx)
+	}
 
-		// Fetch recommender's profile details
-		if elem.RecommenderProfileURN != "" {
-			profile, err := vc.fetchProfile(ctx, elem.RecommenderProfileURN)
-			if err != nil {
-				log.Printf("WARNING: could not fetch profile for recommender: %v", err)
-			} else {
-				rec.Name = strings.TrimSpace(profile.FirstName + " " + profile.LastName)
-				rec.Role = profile.Headline
-				if profile.PublicIdentifier != "" {
-					rec.LinkedInURL = "https://www.linkedin.com/in/" + profile.PublicIdentifier
-				}
-				rec.AvatarURL = extractAvatarURL(profile.ProfilePicture)
-			}
+	endpoint := fmt.Sprintf("%s/identity/dash/profiles?q=memberIdentity&memberIdentity=%s",
+		vc.baseURL, url.QueryEscape(username))
+	req, err := vc.newRequest(ctx, "GET", endpoint)
+	if err != nil {
+		return "", err
+	}
 
-			// Fetch company separately (requires decoration)
-			company, err := vc.fetchCompanyByURN(ctx, elem.RecommenderProfileURN)
-			if err != nil {
-				log.Printf("WARNING: could not fetch company for %s: %v", rec.Name, err)
-			} else {
-				rec.Company = company
-			}
-		}
+	resp, err := vc.do(req)
+	if err != nil {
+		return "", fmt.Errorf("look up %s: %w", username, err)
+	}
+	defer resp.Body.Close()
 
-		if rec.Name != "" && rec.Quote != "" {
-			recs = append(recs, rec)
+	if resp.StatusCode != 200 {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return "", fmt.Errorf("profile lookup returned %d: could not read body: %w", resp.StatusCode, err)
+		}
+		if sessionRejected(resp, body) {
+			return "", fmt.Errorf("profile lookup returned %d: %w", resp.StatusCode, ErrCookieExpired)
+		}
+		vc.protocolHint(resp.StatusCode, body)
+		if resp.StatusCode == http.StatusNotFound {
+			return "", fmt.Errorf("no LinkedIn profile with public identifier %q", username)
 		}
+		return "", &httpx.StatusError{Op: "profile lookup", StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
-	return recs, nil
+	if isHTML(resp) {
+		return "", fmt.Errorf("profile lookup returned a web page instead of JSON: %w", ErrCookieExpired)
+	}
+
+	var result struct {
+		Elements []struct {
+			EntityURN string `json:"entityUrn"`
+		} `json:"elements"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("decode profile lookup: %w", err)
+	}
+	if len(result.Elements) == 0 || result.Elements[0].EntityURN == "" {
+		return "", fmt.Errorf("no LinkedIn profile with public identifier %q", username)
+	}
+	return result.Elements[0].EntityURN, nil
 }
 
 // fetchProfileURN calls /me to get the logged-in user's profile URN.
 func (vc *voyagerClient) fetchProfileURN(ctx context.Context) (string, error) {
-	req, err := vc.newRequest(ctx, "GET", voyagerBaseURL+"/me")
+	req, err := vc.newRequest(ctx, "GET", vc.baseURL+"/me")
 	if err != nil {
 		return "", err
 	}
 
-	resp, err := vc.httpClient.Do(req)
+	resp, err := vc.do(req)
 	if err != nil {
 		return "", fmt.Errorf("fetch /me: %w", err)
 	}
@@ -151,8 +482,15 @@ func (vc *voyagerClient) fetchProfileURN(ctx context.Context) (string, error) {
 		if err != nil {
 			return "", fmt.Errorf("/me returned %d: could not read body: %w", resp.StatusCode, err)
 		}
+		if sessionRejected(resp, body) {
+			return "", fmt.Errorf("/me returned %d: %w", resp.StatusCode, ErrCookieExpired)
+		}
+		vc.protocolHint(resp.StatusCode, body)
 		return "", fmt.Errorf("/me returned %d: %s", resp.StatusCode, string(body))
 	}
+	if isHTML(resp) {
+		return "", fmt.Errorf("/me returned a web page instead of JSON: %w", ErrCookieExpired)
+	}
 
 	var result struct {
 		MiniProfile struct {
@@ -171,52 +509,92 @@ func (vc *voyagerClient) fetchProfileURN(ctx context.Context) (string, error) {
 }
 
 // fetchProfile fetches a profile by URN (no decoration) to get basic info.
+// It asks for only the fields in profileProjection and falls back to the
+// full profile if Voyager rejects the projection.
 func (vc *voyagerClient) fetchProfile(ctx context.Context, profileURN string) (*dashProfile, error) {
 	encodedURN := url.PathEscape(profileURN)
-	endpoint := fmt.Sprintf("%s/identity/dash/profiles/%s", voyagerBaseURL, encodedURN)
+	endpoint := fmt.Sprintf("%s/identity/dash/profiles/%s", vc.baseURL, encodedURN)
+
+	vc.mu.Lock()
+	noProjection := vc.noProjection
+	vc.mu.Unlock()
+	if !noProjection {
+		profile, status, err := vc.getProfile(ctx, endpoint+"?fields="+profileProjection)
+		if status != http.StatusBadRequest {
+			return profile, err
+		}
+		vc.mu.Lock()
+		if !vc.noProjection {
+			vc.logger.Warn("Profile API rejected field projection; fetching full profiles")
+			vc.noProjection = true
+		}
+		vc.mu.Unlock()
+	}
 
+	profile, _, err := vc.getProfile(ctx, endpoint)
+	return profile, err
+}
+
+// getProfile requests and decodes a dashProfile, returning the HTTP status
+// so callers can react to specific failures.
+func (vc *voyagerClient) getProfile(ctx context.Context, endpoint string) (*dashProfile, int, error) {
 	req, err := vc.newRequest(ctx, "GET", endpoint)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	resp, err := vc.httpClient.Do(req)
+	resp, err := vc.do(req)
 	if err != nil {
-		return nil, fmt.Errorf("fetch profile: %w", err)
+		return nil, 0, fmt.Errorf("fetch profile: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, resp.StatusCode, fmt.Errorf("profile API: %w", ErrRateLimited)
+	}
 	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("profile API returned %d", resp.StatusCode)
+		return nil, resp.StatusCode, fmt.Errorf("profile API returned %d", resp.StatusCode)
 	}
 
 	var profile dashProfile
 	if err := json.NewDecoder(resp.Body).Decode(&profile); err != nil {
-		return nil, fmt.Errorf("decode profile: %w", err)
+		return nil, resp.StatusCode, fmt.Errorf("decode profile: %w", err)
 	}
 
-	return &profile, nil
+	return &profile, resp.StatusCode, nil
+}
+
+// topCard holds the supplementary details read from the decorated profile.
+type topCard struct {
+	Company string
+	// MutualConnections is nil when the response carries no mutual
+	// connection insight.
+	MutualConnections *int
 }
 
 // fetchCompanyByURN fetches company name from a profile URN using TopCardSupplementary decoration.
-func (vc *voyagerClient) fetchCompanyByURN(ctx context.Context, profileURN string) (string, error) {
+// The same response also yields the mutual connection count when LinkedIn exposes it.
+func (vc *voyagerClient) fetchCompanyByURN(ctx context.Context, profileURN string) (topCard, error) {
 	encodedURN := url.PathEscape(profileURN)
 	endpoint := fmt.Sprintf("%s/identity/dash/profiles/%s?decorationId=%s",
-		voyagerBaseURL, encodedURN, profileDecoration)
+		vc.baseURL, encodedURN, profileDecoration)
 
 	req, err := vc.newRequest(ctx, "GET", endpoint)
 	if err != nil {
-		return "", err
+		return topCard{}, err
 	}
 
-	resp, err := vc.httpClient.Do(req)
+	resp, err := vc.do(req)
 	if err != nil {
-		return "", fmt.Errorf("fetch decorated profile: %w", err)
+		return topCard{}, fmt.Errorf("fetch decorated profile: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return topCard{}, fmt.Errorf("decorated profile API: %w", ErrRateLimited)
+	}
 	if resp.StatusCode != 200 {
-		return "", fmt.Errorf("decorated profile API returned %d", resp.StatusCode)
+		return topCard{}, fmt.Errorf("decorated profile API returned %d", resp.StatusCode)
 	}
 
 	var result struct {
@@ -225,43 +603,79 @@ func (vc *voyagerClient) fetchCompanyByURN(ctx context.Context, profileURN strin
 				CompanyName string `json:"companyName"`
 			} `json:"elements"`
 		} `json:"profileTopPosition"`
+		ProfileInsight struct {
+			Elements []struct {
+				SharedConnection *struct {
+					SharedConnectionsCount int `json:"sharedConnectionsCount"`
+				} `json:"sharedConnection"`
+			} `json:"elements"`
+		} `json:"profileInsight"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("decode decorated profile: %w", err)
+		return topCard{}, fmt.Errorf("decode decorated profile: %w", err)
 	}
 
+	var card topCard
 	positions := result.ProfileTopPosition.Elements
-	if len(positions) > 0 && positions[0].CompanyName != "" {
-		return positions[0].CompanyName, nil
+	if len(positions) > 0 {
+		card.Company = positions[0].CompanyName
+	}
+	for _, insight := range result.ProfileInsight.Elements {
+		if insight.SharedConnection != nil {
+			n := insight.SharedConnection.SharedConnectionsCount
+			card.MutualConnections = &n
+			break
+		}
 	}
 
-	return "", nil
+	return card, nil
 }
 
-// extractAvatarURL picks the best avatar URL from a dashProfile's ProfilePicture.
-func extractAvatarURL(pic *dashProfilePicture) string {
+// protocolHint logs where to adjust the API settings when a failure looks
+// like LinkedIn no longer accepts the configured protocol version or path.
+func (vc *voyagerClient) protocolHint(status int, body []byte) {
+	lower := strings.ToLower(string(body))
+	if status == http.StatusNotFound || status == http.StatusNotAcceptable ||
+		strings.Contains(lower, "restli") || strings.Contains(lower, "protocol") {
+		vc.logger.Warn("LinkedIn may have changed its API; "+
+			"try --voyager-base-url / --restli-protocol-version or LINKEDIN_VOYAGER_BASE_URL / LINKEDIN_RESTLI_PROTOCOL_VERSION",
+			"base", vc.baseURL, "protocol", vc.protocolVersion)
+	}
+}
+
+// extractAvatarURL picks the avatar artifact from a dashProfile's
+// ProfilePicture whose width is closest to preferredWidth without going
+// below it, or the largest one when none is that wide. It returns the URL
+// and the chosen width, or "" and 0 when there is no picture.
+func extractAvatarURL(pic *dashProfilePicture, preferredWidth int) (string, int) {
 	if pic == nil || pic.DisplayImage == nil || pic.DisplayImage.VectorImage == nil {
-		return ""
+		return "", 0
 	}
 	vi := pic.DisplayImage.VectorImage
-	if vi.RootURL == "" || len(vi.Artifacts) == 0 {
-		return ""
+	if vi.RootURL == "" {
+		return "", 0
 	}
 
-	bestPath := ""
-	for _, a := range vi.Artifacts {
-		if bestPath == "" {
-			bestPath = a.FileIdentifyingURLPathSegment
+	var best, largest *dashArtifact
+	for i := range vi.Artifacts {
+		a := &vi.Artifacts[i]
+		if a.FileIdentifyingURLPathSegment == "" {
+			continue
 		}
-		if a.Width == 200 {
-			bestPath = a.FileIdentifyingURLPathSegment
-			break
+		if largest == nil || a.Width > largest.Width {
+			largest = a
+		}
+		if a.Width >= preferredWidth && (best == nil || a.Width < best.Width) {
+			best = a
 		}
 	}
-	if bestPath == "" {
-		return ""
+	if best == nil {
+		best = largest
+	}
+	if best == nil {
+		return "", 0
 	}
-	return vi.RootURL + bestPath
+	return vi.RootURL + best.FileIdentifyingURLPathSegment, best.Width
 }
 
 // fetchCSRFToken makes a GET to linkedin.com to obtain the JSESSIONID cookie.
@@ -296,7 +710,27 @@ func fetchCSRFToken(ctx context.Context, _ *http.Client, liAtCookie string) (str
 		}
 	}
 
-	return "", fmt.Errorf("JSESSIONID cookie not found — li_at cookie may be expired")
+	return "", fmt.Errorf("JSESSIONID cookie not found: %w", ErrCookieExpired)
+}
+
+// sessionRejected reports whether a failed Voyager response means LinkedIn
+// no longer accepts the li_at cookie: 401/403, the 999 status LinkedIn uses
+// for bot challenges, or a login page served in place of the API.
+func sessionRejected(resp *http.Response, body []byte) bool {
+	switch resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, 999:
+		return true
+	}
+	if !isHTML(resp) {
+		return false
+	}
+	lower := strings.ToLower(string(body))
+	return strings.Contains(lower, "login") || strings.Contains(lower, "authwall") || strings.Contains(lower, "checkpoint")
+}
+
+// isHTML reports whether resp carries a web page rather than API JSON.
+func isHTML(resp *http.Response) bool {
+	return strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html")
 }
 
 // --- Response types for the dash API ---
@@ -306,16 +740,57 @@ type dashRecommendationsResponse struct {
 }
 
 type dashRecommendation struct {
-	RecommendationText   string `json:"recommendationText"`
+	RecommendationText    string `json:"recommendationText"`
 	RecommenderProfileURN string `json:"recommenderProfileUrn"`
+	// Created (epoch milliseconds) and Relationship (an enum such as
+	// MANAGED_DIRECTLY) are kept raw so an unexpected shape only loses
+	// that field instead of failing the whole response.
+	Created      json.RawMessage `json:"created"`
+	Relationship json.RawMessage `json:"relationship"`
+}
+
+// newRecommendation returns the Recommendation fields that come from the
+// recommendation itself rather than the recommender's profile.
+func newRecommendation(elem dashRecommendation) Recommendation {
+	rec := Recommendation{Quote: elem.RecommendationText}
+	var millis int64
+	if json.Unmarshal(elem.Created, &millis) == nil && millis > 0 {
+		rec.CreatedAt = time.UnixMilli(millis).UTC().Format(time.RFC3339)
+	}
+	var relationship string
+	if json.Unmarshal(elem.Relationship, &relationship) == nil && relationship != "" {
+		rec.Relationship = strings.ToLower(strings.ReplaceAll(relationship, "_", " "))
+	}
+	return rec
 }
 
 type dashProfile struct {
-	FirstName        string               `json:"firstName"`
-	LastName         string               `json:"lastName"`
-	Headline         string               `json:"headline"`
-	PublicIdentifier string               `json:"publicIdentifier"`
-	ProfilePicture   *dashProfilePicture  `json:"profilePicture"`
+	FirstName        string              `json:"firstName"`
+	LastName         string              `json:"lastName"`
+	Headline         string              `json:"headline"`
+	PublicIdentifier string              `json:"publicIdentifier"`
+	ProfilePicture   *dashProfilePicture `json:"profilePicture"`
+	// Pronoun is an enum such as HE_HIM; CustomPronoun is free text used
+	// when the member picked "custom".
+	Pronoun       string `json:"pronoun"`
+	CustomPronoun string `json:"customPronoun"`
+}
+
+// pronouns returns the member's pronouns in display form, or "" if unset.
+func (p *dashProfile) pronouns() string {
+	if p.CustomPronoun != "" {
+		return p.CustomPronoun
+	}
+	switch p.Pronoun {
+	case "HE_HIM":
+		return "he/him"
+	case "SHE_HER":
+		return "she/her"
+	case "THEY_THEM":
+		return "they/them"
+	default:
+		return ""
+	}
 }
 
 type dashProfilePicture struct {
@@ -332,6 +807,6 @@ type dashVectorImage struct {
 }
 
 type dashArtifact struct {
-	Width                        int    `json:"width"`
+	Width                         int    `json:"width"`
 	FileIdentifyingURLPathSegment string `json:"fileIdentifyingUrlPathSegment"`
 }
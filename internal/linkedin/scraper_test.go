@@ -0,0 +1,58 @@
+package linkedin
+
+import (
+	"strconv"
+	"testing"
+)
+
+// picture builds a dashProfilePicture with one artifact per width, each
+// served from the path segment "/<width>".
+func picture(widths ...int) *dashProfilePicture {
+	vi := &dashVectorImage{RootURL: "https://media.licdn.com/img"}
+	for _, w := range widths {
+		vi.Artifacts = append(vi.Artifacts, dashArtifact{Width: w, FileIdentifyingURLPathSegment: "/" + strconv.Itoa(w)})
+	}
+	return &dashProfilePicture{DisplayImage: &dashDisplayImage{VectorImage: vi}}
+}
+
+func TestExtractAvatarURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		pic       *dashProfilePicture
+		preferred int
+		wantURL   string
+		wantWidth int
+	}{
+		{"exact width", picture(100, 200, 400, 800), 200, "https://media.licdn.com/img/200", 200},
+		{"next size above", picture(100, 400, 800), 200, "https://media.licdn.com/img/400", 400},
+		{"next size above, unordered", picture(800, 100, 400), 200, "https://media.licdn.com/img/400", 400},
+		{"falls back to largest", picture(100, 50, 150), 200, "https://media.licdn.com/img/150", 150},
+		{"nil picture", nil, 200, "", 0},
+		{"no vector image", &dashProfilePicture{DisplayImage: &dashDisplayImage{}}, 200, "", 0},
+		{"no artifacts", picture(), 200, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotURL, gotWidth := extractAvatarURL(tt.pic, tt.preferred)
+			if gotURL != tt.wantURL || gotWidth != tt.wantWidth {
+				t.Errorf("extractAvatarURL() = %q, %d, want %q, %d", gotURL, gotWidth, tt.wantURL, tt.wantWidth)
+			}
+		})
+	}
+}
+
+func TestExtractAvatarURLSkipsArtifactsWithoutPath(t *testing.T) {
+	pic := picture(200, 400)
+	pic.DisplayImage.VectorImage.Artifacts[0].FileIdentifyingURLPathSegment = ""
+	if url, width := extractAvatarURL(pic, 200); url != "https://media.licdn.com/img/400" || width != 400 {
+		t.Errorf("extractAvatarURL() = %q, %d, want the 400px artifact", url, width)
+	}
+}
+
+func TestExtractAvatarURLWithoutRootURL(t *testing.T) {
+	pic := picture(200)
+	pic.DisplayImage.VectorImage.RootURL = ""
+	if url, width := extractAvatarURL(pic, 200); url != "" || width != 0 {
+		t.Errorf("extractAvatarURL() = %q, %d, want \"\", 0", url, width)
+	}
+}
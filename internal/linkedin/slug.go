@@ -0,0 +1,28 @@
+package linkedin
+
+import (
+	"net/url"
+	"strings"
+)
+
+// SlugFromURL extracts the lowercased public identifier from a LinkedIn
+// profile URL such as https://www.linkedin.com/in/jane-doe/?trk=x.
+// It returns "" when the URL isn't a profile URL.
+func SlugFromURL(profileURL string) string {
+	u, err := url.Parse(strings.TrimSpace(profileURL))
+	if err != nil {
+		return ""
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := 0; i+1 < len(segments); i++ {
+		if segments[i] == "in" && segments[i+1] != "" {
+			slug, err := url.PathUnescape(segments[i+1])
+			if err != nil {
+				slug = segments[i+1]
+			}
+			return strings.ToLower(slug)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,31 @@
+package linkedin
+
+import "testing"
+
+func TestSlugFromURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://www.linkedin.com/in/jane-doe", "jane-doe"},
+		{"https://www.linkedin.com/in/jane-doe/", "jane-doe"},
+		{"https://www.linkedin.com/in/jane-doe//", "jane-doe"},
+		{"https://www.linkedin.com/in/jane-doe?trk=profile", "jane-doe"},
+		{"https://www.linkedin.com/in/jane-doe/?trk=profile&utm_source=x", "jane-doe"},
+		{"https://www.linkedin.com/in/jane-doe#about", "jane-doe"},
+		{"https://www.linkedin.com/in/Jane-Doe/", "jane-doe"},
+		{"  https://linkedin.com/in/jane-doe/  ", "jane-doe"},
+		{"https://www.linkedin.com/in/jane-doe/details/recommendations/", "jane-doe"},
+		{"https://www.linkedin.com/in/jos%C3%A9-p%C3%A9rez/", "josé-pérez"},
+		{"https://www.linkedin.com/company/acme/", ""},
+		{"https://www.linkedin.com/in/", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if got := SlugFromURL(tt.url); got != tt.want {
+				t.Errorf("SlugFromURL(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package linkedin
+
+import (
+	"context"
+	gosync "sync"
+	"time"
+)
+
+// throttle spaces requests at least interval apart, across all goroutines
+// sharing it. A nil throttle or zero interval never waits.
+type throttle struct {
+	mu       gosync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// newThrottle returns a throttle allowing perSecond requests per second.
+// perSecond <= 0 disables throttling.
+func newThrottle(perSecond float64) *throttle {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &throttle{interval: time.Duration(float64(time.Second) / perSecond)}
+}
+
+// wait blocks until the caller's turn, or until ctx is done.
+func (t *throttle) wait(ctx context.Context) error {
+	if t == nil || t.interval <= 0 {
+		return nil
+	}
+	t.mu.Lock()
+	at := time.Now()
+	if t.next.After(at) {
+		at = t.next
+	}
+	t.next = at.Add(t.interval)
+	t.mu.Unlock()
+
+	d := time.Until(at)
+	if d <= 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
@@ -3,9 +3,26 @@ package linkedin
 // Recommendation represents a single LinkedIn recommendation as scraped.
 type Recommendation struct {
 	Name        string `json:"name"`
+	FirstName   string `json:"firstName,omitempty"`
+	LastName    string `json:"lastName,omitempty"`
 	Role        string `json:"role"`
 	Company     string `json:"company"`
 	Quote       string `json:"quote"`
 	AvatarURL   string `json:"avatarUrl,omitempty"`
 	LinkedInURL string `json:"linkedInUrl,omitempty"`
+	QuoteLang   string `json:"quoteLang,omitempty"`
+	Pronouns    string `json:"pronouns,omitempty"`
+	// CreatedAt (RFC 3339) is when the recommendation was written, and
+	// Relationship how the two people worked together, e.g. "managed
+	// directly". Either may be empty.
+	CreatedAt    string `json:"createdAt,omitempty"`
+	Relationship string `json:"relationship,omitempty"`
+	// MutualConnections is the recommender's mutual connection count, when
+	// LinkedIn exposes it.
+	MutualConnections *int `json:"mutualConnections,omitempty"`
+	// SourceProfile, ScrapedAt (RFC 3339) and ToolVersion record where and
+	// when this entry was last scraped, and by which build of this tool.
+	SourceProfile string `json:"sourceProfile,omitempty"`
+	ScrapedAt     string `json:"scrapedAt,omitempty"`
+	ToolVersion   string `json:"toolVersion,omitempty"`
 }
@@ -0,0 +1,128 @@
+package sync
+
+import (
+	"strings"
+
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/contentful"
+)
+
+// Duplicate pairs two testimonials judged to be the same recommendation.
+// Keep and Drop are indices into the list passed to FindDuplicates.
+type Duplicate struct {
+	Keep int
+	Drop int
+	// EmptyCompany marks a pair matched only by name because one side had
+	// no company.
+	EmptyCompany bool
+}
+
+// FindDuplicates reports testimonials that share a name + company key with
+// an earlier one; the earlier entry is kept.
+//
+// With allowEmptyCompany it also pairs entries whose names match when
+// exactly one of them has an empty company. Such pairs come from syncs that
+// ran before company lookups worked; the entry with the company is kept.
+func FindDuplicates(testimonials []contentful.Testimonial, allowEmptyCompany bool) []Duplicate {
+	var dups []Duplicate
+	firstByKey := make(map[string]int)
+	dropped := make(map[int]bool)
+	for i, t := range testimonials {
+		key := dedupeKey(t.Name, t.Company)
+		if first, ok := firstByKey[key]; ok {
+			dups = append(dups, Duplicate{Keep: first, Drop: i})
+			dropped[i] = true
+			continue
+		}
+		firstByKey[key] = i
+	}
+	if !allowEmptyCompany {
+		return dups
+	}
+
+	// Pair each remaining company-less entry with one same-named entry
+	// that has a company.
+	withCompany := make(map[string]int)
+	for i, t := range testimonials {
+		if !dropped[i] && strings.TrimSpace(t.Company) != "" {
+			name := strings.ToLower(strings.TrimSpace(t.Name))
+			if _, ok := withCompany[name]; !ok {
+				withCompany[name] = i
+			}
+		}
+	}
+	for i, t := range testimonials {
+		if dropped[i] || strings.TrimSpace(t.Company) != "" {
+			continue
+		}
+		if keep, ok := withCompany[strings.ToLower(strings.TrimSpace(t.Name))]; ok {
+			dups = append(dups, Duplicate{Keep: keep, Drop: i, EmptyCompany: true})
+		}
+	}
+	return dups
+}
+
+// RemoveDuplicates drops every Duplicate.Drop entry. For empty-company pairs
+// the kept entry takes the richer of the two as its base, with the resolved
+// company, and fills any remaining blank fields from the other.
+func RemoveDuplicates(testimonials []contentful.Testimonial, dups []Duplicate) []contentful.Testimonial {
+	merged := make([]contentful.Testimonial, len(testimonials))
+	copy(merged, testimonials)
+
+	drop := make(map[int]bool, len(dups))
+	for _, d := range dups {
+		drop[d.Drop] = true
+		if d.EmptyCompany {
+			merged[d.Keep] = mergeEmptyCompany(merged[d.Keep], testimonials[d.Drop])
+		}
+	}
+
+	result := make([]contentful.Testimonial, 0, len(testimonials)-len(drop))
+	for i, t := range merged {
+		if !drop[i] {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// mergeEmptyCompany combines an entry with a company and its company-less
+// twin, preferring whichever has more fields filled in.
+func mergeEmptyCompany(withCompany, without contentful.Testimonial) contentful.Testimonial {
+	base, other := withCompany, without
+	if filledFields(without) > filledFields(withCompany) {
+		base, other = without, withCompany
+	}
+	base.Company = withCompany.Company
+
+	fill := func(dst *string, src string) {
+		if strings.TrimSpace(*dst) == "" {
+			*dst = src
+		}
+	}
+	fill(&base.FirstName, other.FirstName)
+	fill(&base.LastName, other.LastName)
+	fill(&base.Role, other.Role)
+	fill(&base.Quote, other.Quote)
+	fill(&base.AvatarURL, other.AvatarURL)
+	fill(&base.LinkedInURL, other.LinkedInURL)
+	fill(&base.QuoteLang, other.QuoteLang)
+	fill(&base.Pronouns, other.Pronouns)
+	if base.MutualConnections == nil {
+		base.MutualConnections = other.MutualConnections
+	}
+	if base.Order == 0 {
+		base.Order = other.Order
+	}
+	return base
+}
+
+// filledFields counts the non-empty descriptive fields of t.
+func filledFields(t contentful.Testimonial) int {
+	n := 0
+	for _, f := range []string{t.Role, t.Quote, t.AvatarURL, t.LinkedInURL, t.QuoteLang, t.Pronouns} {
+		if strings.TrimSpace(f) != "" {
+			n++
+		}
+	}
+	return n
+}
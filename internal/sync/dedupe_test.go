@@ -0,0 +1,122 @@
+package sync
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/contentful"
+)
+
+func TestFindDuplicatesAllowEmptyCompany(t *testing.T) {
+	tests := []struct {
+		name  string
+		in    []contentful.Testimonial
+		allow bool
+		want  []Duplicate
+	}{
+		{
+			name: "empty company on the later entry",
+			in: []contentful.Testimonial{
+				{Name: "Ana", Company: "Acme"},
+				{Name: "ana ", Company: ""},
+			},
+			allow: true,
+			want:  []Duplicate{{Keep: 0, Drop: 1, EmptyCompany: true}},
+		},
+		{
+			name: "empty company on the earlier entry",
+			in: []contentful.Testimonial{
+				{Name: "Ana", Company: " "},
+				{Name: "Ana", Company: "Acme"},
+			},
+			allow: true,
+			want:  []Duplicate{{Keep: 1, Drop: 0, EmptyCompany: true}},
+		},
+		{
+			name: "not paired without allowEmptyCompany",
+			in: []contentful.Testimonial{
+				{Name: "Ana", Company: "Acme"},
+				{Name: "Ana"},
+			},
+			allow: false,
+			want:  nil,
+		},
+		{
+			name: "different companies never match",
+			in: []contentful.Testimonial{
+				{Name: "Ana", Company: "Acme"},
+				{Name: "Ana", Company: "Initech"},
+			},
+			allow: true,
+			want:  nil,
+		},
+		{
+			name: "different names never match",
+			in: []contentful.Testimonial{
+				{Name: "Ana", Company: "Acme"},
+				{Name: "Ben"},
+			},
+			allow: true,
+			want:  nil,
+		},
+		{
+			name: "exact duplicates are still found",
+			in: []contentful.Testimonial{
+				{Name: "Ana", Company: "Acme"},
+				{Name: "ANA", Company: "acme"},
+				{Name: "Ana"},
+			},
+			allow: true,
+			want: []Duplicate{
+				{Keep: 0, Drop: 1},
+				{Keep: 0, Drop: 2, EmptyCompany: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FindDuplicates(tt.in, tt.allow); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindDuplicates() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemoveDuplicatesKeepsRicherEntry(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []contentful.Testimonial
+		want contentful.Testimonial
+	}{
+		{
+			name: "company-less entry is richer",
+			in: []contentful.Testimonial{
+				{Name: "Ana", Company: "Acme", Quote: "Short"},
+				{Name: "Ana", Role: "Engineer", Quote: "A longer quote", AvatarURL: "https://img/ana.jpg",
+					LinkedInURL: "https://www.linkedin.com/in/ana"},
+			},
+			want: contentful.Testimonial{Name: "Ana", Company: "Acme", Role: "Engineer", Quote: "A longer quote",
+				AvatarURL: "https://img/ana.jpg", LinkedInURL: "https://www.linkedin.com/in/ana"},
+		},
+		{
+			name: "entry with company is richer",
+			in: []contentful.Testimonial{
+				{Name: "Ana", Quote: "Old", Pronouns: "she/her"},
+				{Name: "Ana", Company: "Acme", Role: "Engineer", Quote: "New", AvatarURL: "https://img/ana.jpg"},
+			},
+			want: contentful.Testimonial{Name: "Ana", Company: "Acme", Role: "Engineer", Quote: "New",
+				AvatarURL: "https://img/ana.jpg", Pronouns: "she/her"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RemoveDuplicates(tt.in, FindDuplicates(tt.in, true))
+			if len(got) != 1 {
+				t.Fatalf("RemoveDuplicates() kept %d entries, want 1", len(got))
+			}
+			if !reflect.DeepEqual(got[0], tt.want) {
+				t.Errorf("kept %+v, want %+v", got[0], tt.want)
+			}
+		})
+	}
+}
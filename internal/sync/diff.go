@@ -0,0 +1,92 @@
+package sync
+
+import (
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/contentful"
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/linkedin"
+)
+
+// DiffResult describes how scraped recommendations relate to existing
+// testimonials, using the same dedupe rules as Merge.
+type DiffResult struct {
+	// Added are scraped recommendations a Merge would append.
+	Added []contentful.Testimonial `json:"added"`
+	// Changed are existing testimonials whose quote, role or company
+	// differs from their scraped counterpart.
+	Changed []Change `json:"changed"`
+	// OnlyExisting are testimonials with no scraped counterpart: either
+	// manually curated or removed on LinkedIn.
+	OnlyExisting []contentful.Testimonial `json:"onlyExisting"`
+}
+
+// Change pairs a stored testimonial with the values LinkedIn now has.
+type Change struct {
+	Existing contentful.Testimonial `json:"existing"`
+	// Fields lists what differs: "quote", "role" and/or "company".
+	Fields []string `json:"fields"`
+	// Quote, Role and Company are the scraped values.
+	Quote   string `json:"quote"`
+	Role    string `json:"role"`
+	Company string `json:"company"`
+}
+
+// Diff compares existing testimonials with scraped recommendations without
+// modifying either.
+func Diff(existing []contentful.Testimonial, scraped []linkedin.Recommendation, opts MergeOptions) DiffResult {
+	var result DiffResult
+
+	existingSeen := newSeenSet(opts.Strategy)
+	index := newExistingIndex(opts.Strategy)
+	for i, t := range existing {
+		existingSeen.add(t.Name, t.Company, t.LinkedInURL)
+		index.add(i, t)
+	}
+	scrapedSeen := newSeenSet(opts.Strategy)
+	for _, rec := range scraped {
+		scrapedSeen.add(rec.Name, rec.Company, rec.LinkedInURL)
+	}
+
+	changed := make(map[int]bool)
+	for _, rec := range scraped {
+		if existingSeen.has(rec.Name, rec.Company, rec.LinkedInURL) {
+			if i, ok := index.find(rec); ok && !changed[i] {
+				if fields := changedFields(existing[i], rec); len(fields) > 0 {
+					changed[i] = true
+					result.Changed = append(result.Changed, Change{
+						Existing: existing[i],
+						Fields:   fields,
+						Quote:    rec.Quote,
+						Role:     rec.Role,
+						Company:  rec.Company,
+					})
+				}
+			}
+			continue
+		}
+		existingSeen.add(rec.Name, rec.Company, rec.LinkedInURL)
+		result.Added = append(result.Added, ToTestimonial(rec))
+	}
+
+	for _, t := range existing {
+		if !scrapedSeen.has(t.Name, t.Company, t.LinkedInURL) {
+			result.OnlyExisting = append(result.OnlyExisting, t)
+		}
+	}
+
+	return result
+}
+
+// changedFields names the fields Merge with UpdateExisting would overwrite
+// on t. Like updateFields, it ignores translated quotes.
+func changedFields(t contentful.Testimonial, rec linkedin.Recommendation) []string {
+	var fields []string
+	if rec.QuoteLang == "" && t.Quote != rec.Quote {
+		fields = append(fields, "quote")
+	}
+	if t.Role != rec.Role {
+		fields = append(fields, "role")
+	}
+	if t.Company != rec.Company {
+		fields = append(fields, "company")
+	}
+	return fields
+}
@@ -0,0 +1,84 @@
+package sync
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/linkedin"
+)
+
+// Edit is a manual override for a scraped quote.
+type Edit struct {
+	Quote string `json:"quote"`
+	// OriginalHash is the QuoteHash of the scraped quote the edit was
+	// written against. When the scraped quote no longer matches it, the
+	// edit is still applied but reported as stale.
+	OriginalHash string `json:"originalHash,omitempty"`
+}
+
+// StaleEdit reports an edit whose source quote has changed (or was never
+// pinned with an OriginalHash). Hash is the current scraped quote's hash.
+type StaleEdit struct {
+	Key  string
+	Hash string
+}
+
+// LoadEdits reads an edits file: a JSON object mapping a LinkedIn profile
+// slug or a recommender name to an Edit.
+func LoadEdits(path string) (map[string]Edit, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read edits file: %w", err)
+	}
+	var edits map[string]Edit
+	if err := json.Unmarshal(data, &edits); err != nil {
+		return nil, fmt.Errorf("parse edits file %s: %w", path, err)
+	}
+	for key, e := range edits {
+		if strings.TrimSpace(e.Quote) == "" {
+			return nil, fmt.Errorf("edits file %s: empty quote for %q", path, key)
+		}
+	}
+	return edits, nil
+}
+
+// QuoteHash returns the hash ApplyEdits compares against Edit.OriginalHash.
+func QuoteHash(quote string) string {
+	return quoteKey(quote)
+}
+
+// ApplyEdits replaces the quote of every recommendation matching an edit,
+// looking it up by profile slug first and then by name (case-insensitive).
+// It modifies recs in place and returns how many edits were applied and
+// which of those are stale.
+func ApplyEdits(recs []linkedin.Recommendation, edits map[string]Edit) (int, []StaleEdit) {
+	byName := make(map[string]string, len(edits))
+	for key := range edits {
+		byName[strings.ToLower(strings.TrimSpace(key))] = key
+	}
+
+	applied := 0
+	var stale []StaleEdit
+	for i := range recs {
+		key, ok := "", false
+		if slug := linkedin.SlugFromURL(recs[i].LinkedInURL); slug != "" {
+			key, ok = byName[slug]
+		}
+		if !ok {
+			key, ok = byName[strings.ToLower(strings.TrimSpace(recs[i].Name))]
+		}
+		if !ok {
+			continue
+		}
+
+		e := edits[key]
+		if hash := QuoteHash(recs[i].Quote); hash != e.OriginalHash {
+			stale = append(stale, StaleEdit{Key: key, Hash: hash})
+		}
+		recs[i].Quote = e.Quote
+		applied++
+	}
+	return applied, stale
+}
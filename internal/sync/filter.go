@@ -0,0 +1,18 @@
+package sync
+
+import "github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/linkedin"
+
+// FilterMinMutuals drops recommendations whose recommender has fewer than
+// minMutuals mutual connections. Recommendations without a known count are
+// kept, since LinkedIn does not always expose it. It returns the kept
+// recommendations and the number dropped.
+func FilterMinMutuals(recs []linkedin.Recommendation, minMutuals int) ([]linkedin.Recommendation, int) {
+	kept := make([]linkedin.Recommendation, 0, len(recs))
+	for _, rec := range recs {
+		if rec.MutualConnections != nil && *rec.MutualConnections < minMutuals {
+			continue
+		}
+		kept = append(kept, rec)
+	}
+	return kept, len(recs) - len(kept)
+}
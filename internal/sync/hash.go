@@ -0,0 +1,30 @@
+package sync
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/contentful"
+)
+
+// ContentHash returns a stable hash of a testimonials list, sensitive to
+// order and field edits. AvatarURL is left out because rehosting an avatar
+// gives it a new URL on every upload, and provenance because every scrape
+// restamps it.
+func ContentHash(testimonials []contentful.Testimonial) (string, error) {
+	stripped := make([]contentful.Testimonial, len(testimonials))
+	for i, t := range testimonials {
+		t.AvatarURL = ""
+		t.SourceProfile, t.ScrapedAt, t.ToolVersion = "", "", ""
+		stripped[i] = t
+	}
+
+	data, err := json.Marshal(stripped)
+	if err != nil {
+		return "", fmt.Errorf("marshal testimonials: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,115 @@
+package sync
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/contentful"
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/linkedin"
+)
+
+// FieldLimits caps testimonial field lengths in characters. Zero disables
+// the limit for that field.
+type FieldLimits struct {
+	Name    int
+	Role    int
+	Company int
+	Quote   int
+}
+
+// DefaultFieldLimits matches Contentful's Symbol (short text, 256) and
+// Text (long text, 50,000) field types.
+var DefaultFieldLimits = FieldLimits{
+	Name:    256,
+	Role:    256,
+	Company: 256,
+	Quote:   50000,
+}
+
+// Set updates the limit for the named field (name, role, company or quote).
+func (l *FieldLimits) Set(field string, limit int) error {
+	if limit < 0 {
+		return fmt.Errorf("limit for %s must be zero or greater", field)
+	}
+	switch field {
+	case "name":
+		l.Name = limit
+	case "role":
+		l.Role = limit
+	case "company":
+		l.Company = limit
+	case "quote":
+		l.Quote = limit
+	default:
+		return fmt.Errorf("unknown field %q (want name, role, company or quote)", field)
+	}
+	return nil
+}
+
+// FieldViolation describes a field longer than its limit.
+type FieldViolation struct {
+	Field  string
+	Length int
+	Limit  int
+}
+
+func (v FieldViolation) String() string {
+	return fmt.Sprintf("%s is %d characters (limit %d)", v.Field, v.Length, v.Limit)
+}
+
+// ValidateLengths reports every field of t that exceeds its limit.
+func ValidateLengths(t contentful.Testimonial, limits FieldLimits) []FieldViolation {
+	var violations []FieldViolation
+	for _, f := range limitedFields(&t, limits) {
+		if n := utf8.RuneCountInString(*f.value); f.limit > 0 && n > f.limit {
+			violations = append(violations, FieldViolation{Field: f.name, Length: n, Limit: f.limit})
+		}
+	}
+	return violations
+}
+
+// TruncateFields shortens every over-limit field of t in place, ending it
+// with an ellipsis, and reports what was truncated.
+func TruncateFields(t *contentful.Testimonial, limits FieldLimits) []FieldViolation {
+	return truncate(limitedFields(t, limits))
+}
+
+// TruncateRecommendation is TruncateFields for a scraped recommendation.
+// Truncating before Merge lets scraped values compare equal to the
+// truncated ones already stored.
+func TruncateRecommendation(rec *linkedin.Recommendation, limits FieldLimits) []FieldViolation {
+	return truncate([]limitedField{
+		{"name", &rec.Name, limits.Name},
+		{"role", &rec.Role, limits.Role},
+		{"company", &rec.Company, limits.Company},
+		{"quote", &rec.Quote, limits.Quote},
+	})
+}
+
+func truncate(fields []limitedField) []FieldViolation {
+	var violations []FieldViolation
+	for _, f := range fields {
+		n := utf8.RuneCountInString(*f.value)
+		if f.limit <= 0 || n <= f.limit {
+			continue
+		}
+		violations = append(violations, FieldViolation{Field: f.name, Length: n, Limit: f.limit})
+		*f.value = string([]rune(*f.value)[:f.limit-1]) + "…"
+	}
+	return violations
+}
+
+type limitedField struct {
+	name  string
+	value *string
+	limit int
+}
+
+func limitedFields(t *contentful.Testimonial, limits FieldLimits) []limitedField {
+	return []limitedField{
+		{"name", &t.Name, limits.Name},
+		{"role", &t.Role, limits.Role},
+		{"company", &t.Company, limits.Company},
+		{"quote", &t.Quote, limits.Quote},
+	}
+}
@@ -0,0 +1,94 @@
+package sync
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/contentful"
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/linkedin"
+)
+
+func TestValidateLengths(t *testing.T) {
+	limits := FieldLimits{Name: 10, Role: 5, Company: 4, Quote: 0}
+	tm := contentful.Testimonial{
+		Name:    "Ana",
+		Role:    "Engineer",
+		Company: "Initech",
+		Quote:   strings.Repeat("q", 1000),
+	}
+
+	got := ValidateLengths(tm, limits)
+	want := []FieldViolation{
+		{Field: "role", Length: 8, Limit: 5},
+		{Field: "company", Length: 7, Limit: 4},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateLengths() = %+v, want %+v", got, want)
+	}
+	if tm.Role != "Engineer" {
+		t.Errorf("ValidateLengths() modified Role to %q", tm.Role)
+	}
+}
+
+func TestValidateLengthsCountsRunes(t *testing.T) {
+	tm := contentful.Testimonial{Company: "Señor Café"}
+	if got := ValidateLengths(tm, FieldLimits{Company: 10}); len(got) != 0 {
+		t.Errorf("ValidateLengths() = %+v, want none for a 10-rune company", got)
+	}
+}
+
+func TestTruncateFields(t *testing.T) {
+	limits := FieldLimits{Name: 256, Role: 6, Company: 5}
+	tm := contentful.Testimonial{
+		Name:    "Ana",
+		Role:    "Staff Engineer",
+		Company: "Ñandú Labs",
+	}
+
+	violations := TruncateFields(&tm, limits)
+
+	want := []FieldViolation{
+		{Field: "role", Length: 14, Limit: 6},
+		{Field: "company", Length: 10, Limit: 5},
+	}
+	if !reflect.DeepEqual(violations, want) {
+		t.Errorf("TruncateFields() = %+v, want %+v", violations, want)
+	}
+	if tm.Role != "Staff…" {
+		t.Errorf("Role = %q, want %q", tm.Role, "Staff…")
+	}
+	if tm.Company != "Ñand…" {
+		t.Errorf("Company = %q, want %q", tm.Company, "Ñand…")
+	}
+	if tm.Name != "Ana" {
+		t.Errorf("Name = %q, want it unchanged", tm.Name)
+	}
+}
+
+func TestTruncatedRecommendationMatchesStoredEntry(t *testing.T) {
+	limits := FieldLimits{Role: 8, Company: 8}
+	rec := linkedin.Recommendation{
+		Name:    "Ana",
+		Role:    "Principal Engineer",
+		Company: "Initech Holdings",
+		Quote:   "Great",
+	}
+	stored := ToTestimonial(rec)
+	TruncateFields(&stored, limits)
+
+	// A later run scrapes the same recommendation at full length.
+	if got := TruncateRecommendation(&rec, limits); len(got) != 2 {
+		t.Fatalf("TruncateRecommendation() = %+v, want role and company", got)
+	}
+	merged, newIdx, changedIdx := Merge([]contentful.Testimonial{stored}, []linkedin.Recommendation{rec}, MergeOptions{UpdateExisting: true})
+	if len(newIdx) != 0 || len(merged) != 1 {
+		t.Errorf("Merge() added %v, want the truncated entry to match the stored one", newIdx)
+	}
+	if len(changedIdx) != 0 {
+		t.Errorf("Merge() updated %v, want the truncated entry left as stored", changedIdx)
+	}
+	if merged[0] != stored {
+		t.Errorf("merged = %+v, want %+v", merged[0], stored)
+	}
+}
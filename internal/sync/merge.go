@@ -1,44 +1,321 @@
 package sync
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
 	"strings"
 
 	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/contentful"
 	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/linkedin"
 )
 
+// DedupeStrategy selects how Merge recognizes an already-synced recommendation.
+type DedupeStrategy string
+
+const (
+	// DedupeNameCompany keys on normalized name + company.
+	DedupeNameCompany DedupeStrategy = "name"
+	// DedupeSlug keys on the LinkedIn profile slug when both entries have
+	// a LinkedIn URL, falling back to name + company when either lacks one.
+	DedupeSlug DedupeStrategy = "slug"
+)
+
+// MergeOptions tunes Merge. The zero value sorts by Order when existing
+// testimonials carry one.
+type MergeOptions struct {
+	// Strategy defaults to DedupeNameCompany.
+	Strategy DedupeStrategy
+	// AppendOnly only appends new testimonials: existing entries are never
+	// modified or reordered.
+	AppendOnly bool
+	// UpdateExisting overwrites the quote, role and company of an existing
+	// testimonial when its scraped counterpart differs. Ignored with
+	// AppendOnly. Company changes need DedupeSlug: with DedupeNameCompany a
+	// new company means no match, so the recommendation is added as new.
+	UpdateExisting bool
+	// DedupeQuotes also drops scraped recommendations whose normalized quote
+	// text matches one already kept, whatever the name. It is opt-in since
+	// short quotes like "Great mentor!" can come from different people.
+	DedupeQuotes bool
+}
+
 // Merge combines existing Contentful testimonials with newly scraped
 // LinkedIn recommendations. Deduplication uses a composite key of
-// normalized (lowercased, trimmed) name + company.
-// Returns the full merged list and the indices of newly added testimonials.
-func Merge(existing []contentful.Testimonial, scraped []linkedin.Recommendation) ([]contentful.Testimonial, []int) {
-	seen := make(map[string]bool, len(existing))
-	for _, t := range existing {
-		seen[dedupeKey(t.Name, t.Company)] = true
+// normalized (lowercased, trimmed) name + company, or the LinkedIn profile
+// slug when opts.Strategy is DedupeSlug.
+//
+// An existing entry missing its LinkedIn URL, creation date or relationship
+// takes them from the scraped recommendation it matches, unless
+// opts.AppendOnly is set. With
+// opts.UpdateExisting, a matched entry whose quote, role or company differs
+// from the scraped recommendation is overwritten with the scraped values.
+//
+// When any existing testimonial has an Order, new testimonials are numbered
+// after the highest existing Order and the result is sorted by Order, with
+// unordered entries kept at the end in their original sequence.
+//
+// Returns the full merged list, the indices of newly added testimonials and
+// the indices of existing testimonials that were updated.
+func Merge(existing []contentful.Testimonial, scraped []linkedin.Recommendation, opts MergeOptions) ([]contentful.Testimonial, []int, []int) {
+	seen := newSeenSet(opts.Strategy)
+	seenQuotes := make(map[string]bool)
+	index := newExistingIndex(opts.Strategy)
+	maxOrder := 0
+	for i, t := range existing {
+		seen.add(t.Name, t.Company, t.LinkedInURL)
+		index.add(i, t)
+		if opts.DedupeQuotes {
+			seenQuotes[quoteKey(t.Quote)] = true
+		}
+		maxOrder = max(maxOrder, t.Order)
 	}
 
 	result := make([]contentful.Testimonial, len(existing))
 	copy(result, existing)
 
-	var newIndices []int
+	var newIndices, changedIndices []int
+	changed := make(map[int]bool)
 	for _, rec := range scraped {
-		key := dedupeKey(rec.Name, rec.Company)
-		if seen[key] {
+		if seen.has(rec.Name, rec.Company, rec.LinkedInURL) {
+			i, ok := index.find(rec)
+			if !ok || opts.AppendOnly {
+				continue
+			}
+			// Entries synced before these fields were captured pick them
+			// up from the matching recommendation.
+			if result[i].LinkedInURL == "" && rec.LinkedInURL != "" {
+				result[i].LinkedInURL = rec.LinkedInURL
+			}
+			if result[i].CreatedAt == "" && rec.CreatedAt != "" {
+				result[i].CreatedAt = rec.CreatedAt
+			}
+			if result[i].Relationship == "" && rec.Relationship != "" {
+				result[i].Relationship = rec.Relationship
+			}
+			if opts.UpdateExisting && !changed[i] && updateFields(&result[i], rec) {
+				changed[i] = true
+				changedIndices = append(changedIndices, i)
+			}
 			continue
 		}
-		seen[key] = true
+		if opts.DedupeQuotes {
+			qk := quoteKey(rec.Quote)
+			if seenQuotes[qk] {
+				continue
+			}
+			seenQuotes[qk] = true
+		}
+		seen.add(rec.Name, rec.Company, rec.LinkedInURL)
+		t := ToTestimonial(rec)
+		if maxOrder > 0 {
+			maxOrder++
+			t.Order = maxOrder
+		}
 		newIndices = append(newIndices, len(result))
-		result = append(result, contentful.Testimonial{
-			Name:        rec.Name,
-			Role:        rec.Role,
-			Company:     rec.Company,
-			Quote:       rec.Quote,
-			AvatarURL:   rec.AvatarURL,
-			LinkedInURL: rec.LinkedInURL,
-		})
+		result = append(result, t)
+	}
+
+	if opts.AppendOnly || maxOrder == 0 {
+		return result, newIndices, changedIndices
+	}
+	sorted, remapped := sortByOrder(result, newIndices, changedIndices)
+	return sorted, remapped[0], remapped[1]
+}
+
+// updateFields copies the scraped quote, role and company onto t and
+// reports whether any of them differed. Translated quotes are left alone:
+// a fresh translation rarely matches the stored one word for word. When it
+// reports a change, t's provenance is updated to rec's as well.
+func updateFields(t *contentful.Testimonial, rec linkedin.Recommendation) bool {
+	quote := rec.Quote
+	if rec.QuoteLang != "" {
+		quote = t.Quote
+	}
+	if t.Quote == quote && t.Role == rec.Role && t.Company == rec.Company {
+		return false
+	}
+	t.Quote = quote
+	t.Role = rec.Role
+	t.Company = rec.Company
+	t.SourceProfile = rec.SourceProfile
+	t.ScrapedAt = rec.ScrapedAt
+	t.ToolVersion = rec.ToolVersion
+	return true
+}
+
+// sortByOrder stably sorts testimonials by Order, placing entries without an
+// Order last, and remaps each list of indices to the sorted positions.
+func sortByOrder(testimonials []contentful.Testimonial, indexLists ...[]int) ([]contentful.Testimonial, [][]int) {
+	perm := make([]int, len(testimonials))
+	for i := range perm {
+		perm[i] = i
+	}
+	sort.SliceStable(perm, func(a, b int) bool {
+		oa, ob := testimonials[perm[a]].Order, testimonials[perm[b]].Order
+		if oa == 0 || ob == 0 {
+			return oa != 0 && ob == 0
+		}
+		return oa < ob
+	})
+
+	sorted := make([]contentful.Testimonial, len(testimonials))
+	newPos := make([]int, len(testimonials))
+	for pos, idx := range perm {
+		sorted[pos] = testimonials[idx]
+		newPos[idx] = pos
+	}
+
+	remapped := make([][]int, len(indexLists))
+	for l, indices := range indexLists {
+		for _, idx := range indices {
+			remapped[l] = append(remapped[l], newPos[idx])
+		}
+		sort.Ints(remapped[l])
+	}
+	return sorted, remapped
+}
+
+// ToTestimonial converts a scraped recommendation into a Contentful testimonial.
+func ToTestimonial(rec linkedin.Recommendation) contentful.Testimonial {
+	return contentful.Testimonial{
+		Name:              rec.Name,
+		FirstName:         rec.FirstName,
+		LastName:          rec.LastName,
+		Role:              rec.Role,
+		Company:           rec.Company,
+		Quote:             rec.Quote,
+		AvatarURL:         rec.AvatarURL,
+		LinkedInURL:       rec.LinkedInURL,
+		QuoteLang:         rec.QuoteLang,
+		Pronouns:          rec.Pronouns,
+		CreatedAt:         rec.CreatedAt,
+		Relationship:      rec.Relationship,
+		MutualConnections: rec.MutualConnections,
+		SourceProfile:     rec.SourceProfile,
+		ScrapedAt:         rec.ScrapedAt,
+		ToolVersion:       rec.ToolVersion,
+	}
+}
+
+// FromTestimonial converts a stored testimonial back into a recommendation,
+// so imported testimonials can go through Merge. Order is not carried over.
+func FromTestimonial(t contentful.Testimonial) linkedin.Recommendation {
+	return linkedin.Recommendation{
+		Name:              t.Name,
+		FirstName:         t.FirstName,
+		LastName:          t.LastName,
+		Role:              t.Role,
+		Company:           t.Company,
+		Quote:             t.Quote,
+		AvatarURL:         t.AvatarURL,
+		LinkedInURL:       t.LinkedInURL,
+		QuoteLang:         t.QuoteLang,
+		Pronouns:          t.Pronouns,
+		CreatedAt:         t.CreatedAt,
+		Relationship:      t.Relationship,
+		MutualConnections: t.MutualConnections,
+		SourceProfile:     t.SourceProfile,
+		ScrapedAt:         t.ScrapedAt,
+		ToolVersion:       t.ToolVersion,
+	}
+}
+
+// seenSet tracks which testimonials Merge has already kept.
+type seenSet struct {
+	bySlug bool
+	slugs  map[string]bool
+	// names holds name+company keys. With the slug strategy only entries
+	// lacking a slug are recorded here; allNames has every entry, for
+	// lookups that have no slug themselves.
+	names    map[string]bool
+	allNames map[string]bool
+}
+
+func newSeenSet(strategy DedupeStrategy) *seenSet {
+	return &seenSet{
+		bySlug:   strategy == DedupeSlug,
+		slugs:    make(map[string]bool),
+		names:    make(map[string]bool),
+		allNames: make(map[string]bool),
+	}
+}
+
+func (s *seenSet) add(name, company, linkedInURL string) {
+	key := dedupeKey(name, company)
+	s.allNames[key] = true
+	if s.bySlug {
+		if slug := linkedin.SlugFromURL(linkedInURL); slug != "" {
+			s.slugs[slug] = true
+			return
+		}
+	}
+	s.names[key] = true
+}
+
+// has reports whether an entry was kept. With the slug strategy, two
+// entries that both have a LinkedIn URL match only on slug; name+company
+// is the fallback when either side lacks one.
+func (s *seenSet) has(name, company, linkedInURL string) bool {
+	key := dedupeKey(name, company)
+	if s.bySlug {
+		if slug := linkedin.SlugFromURL(linkedInURL); slug != "" {
+			return s.slugs[slug] || s.names[key]
+		}
 	}
+	return s.allNames[key]
+}
+
+// existingIndex finds the existing testimonial a scraped recommendation
+// matches, using the same keys as seenSet.
+type existingIndex struct {
+	bySlug   bool
+	slugs    map[string]int
+	names    map[string]int
+	allNames map[string]int
+}
+
+func newExistingIndex(strategy DedupeStrategy) *existingIndex {
+	return &existingIndex{
+		bySlug:   strategy == DedupeSlug,
+		slugs:    make(map[string]int),
+		names:    make(map[string]int),
+		allNames: make(map[string]int),
+	}
+}
+
+func (x *existingIndex) add(i int, t contentful.Testimonial) {
+	key := dedupeKey(t.Name, t.Company)
+	x.allNames[key] = i
+	if x.bySlug {
+		if slug := linkedin.SlugFromURL(t.LinkedInURL); slug != "" {
+			x.slugs[slug] = i
+			return
+		}
+	}
+	x.names[key] = i
+}
+
+func (x *existingIndex) find(rec linkedin.Recommendation) (int, bool) {
+	key := dedupeKey(rec.Name, rec.Company)
+	if x.bySlug {
+		if slug := linkedin.SlugFromURL(rec.LinkedInURL); slug != "" {
+			if i, ok := x.slugs[slug]; ok {
+				return i, true
+			}
+			i, ok := x.names[key]
+			return i, ok
+		}
+	}
+	i, ok := x.allNames[key]
+	return i, ok
+}
 
-	return result, newIndices
+// quoteKey hashes a quote after lowercasing and collapsing whitespace.
+func quoteKey(quote string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(quote)), " ")
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
 }
 
 func dedupeKey(name, company string) string {
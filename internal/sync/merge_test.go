@@ -0,0 +1,216 @@
+package sync
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/contentful"
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/linkedin"
+)
+
+func TestMergeAppendOnlyLeavesExistingUntouched(t *testing.T) {
+	existing := []contentful.Testimonial{
+		{Name: "Ana", Company: "Acme", Role: "Engineer", Quote: "Old quote", Order: 3},
+		{Name: "Ben", Company: "Initech", Role: "Manager", Quote: "Solid"},
+		{Name: "Cleo", Company: "Globex", Role: "Designer", Quote: "Sharp", Order: 1},
+	}
+	snapshot := make([]contentful.Testimonial, len(existing))
+	copy(snapshot, existing)
+	scraped := []linkedin.Recommendation{
+		// Matches Ana, with fields that differ from the stored entry.
+		{Name: "Ana", Company: "Acme", Role: "Lead Engineer", Quote: "New quote",
+			LinkedInURL: "https://www.linkedin.com/in/ana"},
+		{Name: "Dev", Company: "Hooli", Role: "PM", Quote: "Great PM"},
+	}
+
+	merged, newIdx, changedIdx := Merge(existing, scraped, MergeOptions{AppendOnly: true, UpdateExisting: true})
+
+	if len(merged) != len(snapshot)+1 {
+		t.Fatalf("merged has %d testimonials, want %d", len(merged), len(snapshot)+1)
+	}
+	for i, want := range snapshot {
+		if !reflect.DeepEqual(merged[i], want) {
+			t.Errorf("merged[%d] = %+v, want it unchanged as %+v", i, merged[i], want)
+		}
+		if !reflect.DeepEqual(existing[i], want) {
+			t.Errorf("existing[%d] was mutated to %+v", i, existing[i])
+		}
+	}
+	if len(newIdx) != 1 || newIdx[0] != len(snapshot) || merged[newIdx[0]].Name != "Dev" {
+		t.Errorf("newIdx = %v, want Dev appended at %d", newIdx, len(snapshot))
+	}
+	if len(changedIdx) != 0 {
+		t.Errorf("changedIdx = %v, want none in append-only mode", changedIdx)
+	}
+}
+
+func TestMergeSortsMixedOrderedAndUnordered(t *testing.T) {
+	existing := []contentful.Testimonial{
+		{Name: "Ana", Company: "Acme", Order: 2},
+		{Name: "Ben", Company: "Initech"},
+		{Name: "Cleo", Company: "Globex", Order: 1},
+		{Name: "Dev", Company: "Hooli"},
+	}
+	scraped := []linkedin.Recommendation{
+		{Name: "Ana", Company: "Acme"},
+		{Name: "Eve", Company: "Umbrella"},
+		{Name: "Fay", Company: "Soylent"},
+	}
+
+	merged, newIdx, _ := Merge(existing, scraped, MergeOptions{})
+
+	var names []string
+	var orders []int
+	for _, t := range merged {
+		names = append(names, t.Name)
+		orders = append(orders, t.Order)
+	}
+	// New entries are numbered after the highest Order; unordered entries
+	// keep their sequence at the end.
+	if want := []string{"Cleo", "Ana", "Eve", "Fay", "Ben", "Dev"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("order = %v, want %v", names, want)
+	}
+	if want := []int{1, 2, 3, 4, 0, 0}; !reflect.DeepEqual(orders, want) {
+		t.Errorf("Order fields = %v, want %v", orders, want)
+	}
+	if want := []int{2, 3}; !reflect.DeepEqual(newIdx, want) {
+		t.Errorf("newIdx = %v, want %v", newIdx, want)
+	}
+}
+
+func TestMergeWithoutOrderKeepsSequence(t *testing.T) {
+	existing := []contentful.Testimonial{
+		{Name: "Ben", Company: "Initech"},
+		{Name: "Ana", Company: "Acme"},
+	}
+	scraped := []linkedin.Recommendation{{Name: "Cleo", Company: "Globex"}}
+
+	merged, newIdx, _ := Merge(existing, scraped, MergeOptions{})
+
+	if len(merged) != 3 || merged[0].Name != "Ben" || merged[1].Name != "Ana" || merged[2].Name != "Cleo" {
+		t.Errorf("merged = %+v, want Ben, Ana, Cleo", merged)
+	}
+	if merged[2].Order != 0 {
+		t.Errorf("new entry got Order %d, want 0 when no entry is ordered", merged[2].Order)
+	}
+	if len(newIdx) != 1 || newIdx[0] != 2 {
+		t.Errorf("newIdx = %v, want [2]", newIdx)
+	}
+}
+
+func TestMergeBackfillsLinkedInURL(t *testing.T) {
+	existing := []contentful.Testimonial{
+		{Name: "Ana", Company: "Acme", Quote: "Great", Order: 2},
+		{Name: "Ben", Company: "Initech", Quote: "Solid", Order: 1,
+			LinkedInURL: "https://www.linkedin.com/in/ben"},
+	}
+	scraped := []linkedin.Recommendation{
+		{Name: "ana", Company: "ACME", Quote: "Great", LinkedInURL: "https://www.linkedin.com/in/ana"},
+		{Name: "Ben", Company: "Initech", Quote: "Solid", LinkedInURL: "https://www.linkedin.com/in/ben-2"},
+	}
+
+	merged, newIdx, _ := Merge(existing, scraped, MergeOptions{})
+
+	if len(newIdx) != 0 {
+		t.Fatalf("newIdx = %v, want both recommendations matched", newIdx)
+	}
+	urls := map[string]string{}
+	for _, m := range merged {
+		urls[m.Name] = m.LinkedInURL
+	}
+	if urls["Ana"] != "https://www.linkedin.com/in/ana" {
+		t.Errorf("Ana's LinkedInURL = %q, want it backfilled from the scraped recommendation", urls["Ana"])
+	}
+	if urls["Ben"] != "https://www.linkedin.com/in/ben" {
+		t.Errorf("Ben's LinkedInURL = %q, want the stored URL kept", urls["Ben"])
+	}
+	if existing[0].LinkedInURL != "" {
+		t.Errorf("existing[0] was mutated to %+v", existing[0])
+	}
+}
+
+func TestMergeCompanyChangeNeedsSlugStrategy(t *testing.T) {
+	existing := []contentful.Testimonial{
+		{Name: "Ana", Company: "Acme", Role: "Engineer", Quote: "Great",
+			LinkedInURL: "https://www.linkedin.com/in/ana"},
+	}
+	scraped := []linkedin.Recommendation{
+		{Name: "Ana", Company: "Globex", Role: "Engineer", Quote: "Great",
+			LinkedInURL: "https://www.linkedin.com/in/ana"},
+	}
+
+	_, newIdx, changedIdx := Merge(existing, scraped, MergeOptions{UpdateExisting: true})
+	if len(newIdx) != 1 || len(changedIdx) != 0 {
+		t.Errorf("name strategy: newIdx = %v, changedIdx = %v, want the recommendation added as new", newIdx, changedIdx)
+	}
+
+	merged, newIdx, changedIdx := Merge(existing, scraped, MergeOptions{Strategy: DedupeSlug, UpdateExisting: true})
+	if len(newIdx) != 0 || len(changedIdx) != 1 || merged[0].Company != "Globex" {
+		t.Errorf("slug strategy: merged = %+v, newIdx = %v, changedIdx = %v, want the stored entry updated", merged, newIdx, changedIdx)
+	}
+}
+
+func TestMergeUpdateExistingRefreshesProvenance(t *testing.T) {
+	existing := []contentful.Testimonial{
+		{Name: "Ana", Company: "Acme", Role: "Engineer", Quote: "Old quote",
+			SourceProfile: "old", ScrapedAt: "2025-01-01T00:00:00Z", ToolVersion: "v1"},
+		{Name: "Ben", Company: "Initech", Role: "Manager", Quote: "Same quote",
+			SourceProfile: "old", ScrapedAt: "2025-01-01T00:00:00Z", ToolVersion: "v1"},
+	}
+	scraped := []linkedin.Recommendation{
+		{Name: "Ana", Company: "Acme", Role: "Engineer", Quote: "New quote",
+			SourceProfile: "new", ScrapedAt: "2026-01-01T00:00:00Z", ToolVersion: "v2"},
+		{Name: "Ben", Company: "Initech", Role: "Manager", Quote: "Same quote",
+			SourceProfile: "new", ScrapedAt: "2026-01-01T00:00:00Z", ToolVersion: "v2"},
+	}
+
+	merged, _, changed := Merge(existing, scraped, MergeOptions{UpdateExisting: true})
+
+	if len(changed) != 1 || changed[0] != 0 {
+		t.Fatalf("changed = %v, want [0]", changed)
+	}
+	ana := merged[0]
+	if ana.Quote != "New quote" || ana.SourceProfile != "new" || ana.ScrapedAt != "2026-01-01T00:00:00Z" || ana.ToolVersion != "v2" {
+		t.Errorf("updated entry = %+v, want new quote and provenance", ana)
+	}
+	ben := merged[1]
+	if ben.SourceProfile != "old" || ben.ScrapedAt != "2025-01-01T00:00:00Z" || ben.ToolVersion != "v1" {
+		t.Errorf("untouched entry = %+v, want old provenance", ben)
+	}
+}
+
+func TestDedupeKey(t *testing.T) {
+	if a, b := dedupeKey(" Ana López ", "ACME "), dedupeKey("ana lópez", "acme"); a != b {
+		t.Errorf("dedupeKey differs on case and spacing: %q vs %q", a, b)
+	}
+	if a, b := dedupeKey("Ana", "Acme"), dedupeKey("Ana", "Initech"); a == b {
+		t.Errorf("dedupeKey(%q) matches for different companies", a)
+	}
+}
+
+func TestMergeSlugDedupe(t *testing.T) {
+	existing := []contentful.Testimonial{
+		{Name: "Ana", Company: "Acme", Quote: "Great", LinkedInURL: "https://www.linkedin.com/in/ana/"},
+	}
+	tests := []struct {
+		name    string
+		rec     linkedin.Recommendation
+		wantNew int
+	}{
+		{"same URL", linkedin.Recommendation{Name: "Ana", LinkedInURL: "https://www.linkedin.com/in/ana/"}, 0},
+		{"no trailing slash", linkedin.Recommendation{Name: "Ana", LinkedInURL: "https://www.linkedin.com/in/ana"}, 0},
+		{"query string", linkedin.Recommendation{Name: "Ana", LinkedInURL: "https://www.linkedin.com/in/ana?trk=public_profile"}, 0},
+		{"slash and query string", linkedin.Recommendation{Name: "Ana", LinkedInURL: "https://www.linkedin.com/in/ana/?trk=x"}, 0},
+		{"renamed, same slug", linkedin.Recommendation{Name: "Ana María", Company: "Initech", LinkedInURL: "https://linkedin.com/in/ANA"}, 0},
+		{"no URL, same name and company", linkedin.Recommendation{Name: "ana", Company: "acme"}, 0},
+		{"same name, other slug", linkedin.Recommendation{Name: "Ana", Company: "Acme", LinkedInURL: "https://www.linkedin.com/in/ana-2/"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, newIdx, _ := Merge(existing, []linkedin.Recommendation{tt.rec}, MergeOptions{Strategy: DedupeSlug})
+			if len(newIdx) != tt.wantNew {
+				t.Errorf("Merge added %d testimonials, want %d", len(newIdx), tt.wantNew)
+			}
+		})
+	}
+}
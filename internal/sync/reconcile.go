@@ -0,0 +1,49 @@
+package sync
+
+import (
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/contentful"
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/linkedin"
+)
+
+// Reconcile drops existing testimonials whose LinkedIn profile is no longer
+// among the scraped recommendations. Matching is on the profile slug only,
+// so a recommender whose name or company changed is never taken for a
+// removed one. Testimonials without a LinkedIn profile URL are treated as
+// manually authored and always kept.
+//
+// scraped must be the full current set, enriched with LinkedIn URLs:
+// anything missing from it is assumed to have been removed or hidden on
+// LinkedIn.
+func Reconcile(existing []contentful.Testimonial, scraped []linkedin.Recommendation) (kept, removed []contentful.Testimonial) {
+	slugs := make(map[string]bool, len(scraped))
+	for _, rec := range scraped {
+		if slug := linkedin.SlugFromURL(rec.LinkedInURL); slug != "" {
+			slugs[slug] = true
+		}
+	}
+	for _, t := range existing {
+		if slug := linkedin.SlugFromURL(t.LinkedInURL); slug != "" && !slugs[slug] {
+			removed = append(removed, t)
+			continue
+		}
+		kept = append(kept, t)
+	}
+	return kept, removed
+}
+
+// manualTestimonials returns the existing testimonials without a LinkedIn
+// URL, which were written by hand rather than synced, skipping any that
+// match a scraped recommendation since its LinkedIn copy takes their place.
+func manualTestimonials(existing []contentful.Testimonial, scraped []linkedin.Recommendation, strategy DedupeStrategy) []contentful.Testimonial {
+	seen := newSeenSet(strategy)
+	for _, rec := range scraped {
+		seen.add(rec.Name, rec.Company, rec.LinkedInURL)
+	}
+	var manual []contentful.Testimonial
+	for _, t := range existing {
+		if t.LinkedInURL == "" && !seen.has(t.Name, t.Company, t.LinkedInURL) {
+			manual = append(manual, t)
+		}
+	}
+	return manual
+}
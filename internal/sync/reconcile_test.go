@@ -0,0 +1,42 @@
+package sync
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/contentful"
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/linkedin"
+)
+
+func TestReconcileMatchesOnSlug(t *testing.T) {
+	existing := []contentful.Testimonial{
+		{Name: "Ana", Company: "Acme", LinkedInURL: "https://www.linkedin.com/in/ana"},
+		{Name: "Bo", Company: "Initech", LinkedInURL: "https://www.linkedin.com/in/bo"},
+		{Name: "Cy", Company: "Hooli"},
+	}
+	scraped := []linkedin.Recommendation{
+		// Ana changed name format and company; the slug still matches.
+		{Name: "Ana López", Company: "Globex", LinkedInURL: "https://www.linkedin.com/in/Ana/"},
+	}
+
+	kept, removed := Reconcile(existing, scraped)
+
+	if want := []contentful.Testimonial{existing[0], existing[2]}; !reflect.DeepEqual(kept, want) {
+		t.Errorf("kept = %+v, want %+v", kept, want)
+	}
+	if want := []contentful.Testimonial{existing[1]}; !reflect.DeepEqual(removed, want) {
+		t.Errorf("removed = %+v, want %+v", removed, want)
+	}
+}
+
+func TestReconcileIgnoresNameMatches(t *testing.T) {
+	existing := []contentful.Testimonial{
+		{Name: "Ana", Company: "Acme", LinkedInURL: "https://www.linkedin.com/in/ana"},
+	}
+	// Same name and company but no URL: not proof the profile is still there.
+	scraped := []linkedin.Recommendation{{Name: "Ana", Company: "Acme"}}
+
+	if _, removed := Reconcile(existing, scraped); len(removed) != 1 {
+		t.Errorf("removed = %+v, want the entry whose slug was not scraped", removed)
+	}
+}
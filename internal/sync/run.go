@@ -0,0 +1,399 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/contentful"
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/linkedin"
+)
+
+// ErrFieldTooLong is wrapped by Run's error when RunOptions.Strict rejects
+// a testimonial with a field over its limit.
+var ErrFieldTooLong = errors.New("field exceeds its limit")
+
+// maxConflictRetries bounds how often Run fetches and merges again when
+// the entry changed between its read and write.
+const maxConflictRetries = 3
+
+// Store is the part of the Contentful client Run reads from and writes to.
+type Store interface {
+	GetTestimonials(ctx context.Context) (*contentful.TestimonialsResult, error)
+	CreateTestimonials(ctx context.Context, testimonials []contentful.Testimonial) (string, int, error)
+	UpdateTestimonials(ctx context.Context, result *contentful.TestimonialsResult, testimonials []contentful.Testimonial) (int, error)
+	PublishEntry(ctx context.Context, entryID string, version int) error
+	UploadAvatar(ctx context.Context, imageURL string, owner contentful.AvatarOwner) (contentful.UploadedAsset, error)
+	PendingAssets() []string
+	PublishAssetsBulk(ctx context.Context, ids []string) error
+}
+
+// Deps are the services Run talks to.
+type Deps struct {
+	Store Store
+	// Logger receives progress and non-fatal problems. Nil discards them.
+	Logger *slog.Logger
+}
+
+// RunOptions configures one Run over a section.
+type RunOptions struct {
+	// SectionID labels the report.
+	SectionID string
+	// Recommendations are the scraped recommendations to sync, already
+	// filtered and translated.
+	Recommendations []linkedin.Recommendation
+	// Filtered is how many recommendations were dropped before Run; it is
+	// carried into the report.
+	Filtered int
+	// LookupFailures is how many recommender lookups failed while
+	// scraping; see linkedin.Scrape.
+	LookupFailures int
+	// Force replaces the stored testimonials synced from LinkedIn instead of
+	// merging. Testimonials without a LinkedIn URL were written by hand and
+	// are kept after the scraped ones. It is ignored with Merge.AppendOnly.
+	Force bool
+	Merge MergeOptions
+	// Prune removes stored testimonials from LinkedIn that are no longer
+	// among Recommendations; see Reconcile. It is ignored with Force and
+	// Merge.AppendOnly, and skipped when LookupFailures is non-zero.
+	Prune bool
+	// Limits are applied to new and updated testimonials. Over-long fields
+	// are truncated, or fail the run with ErrFieldTooLong when Strict.
+	Limits FieldLimits
+	Strict bool
+	// LastContentHash is the hash recorded by the previous run. When the
+	// merged content hashes to it, nothing is written unless Force is set.
+	LastContentHash string
+	// NoPublish leaves the entry and any deferred avatar assets as drafts.
+	NoPublish bool
+}
+
+// Outcome actions.
+const (
+	ActionNew     = "new"
+	ActionUpdated = "updated"
+	ActionRemoved = "removed"
+)
+
+// Avatar outcomes.
+const (
+	AvatarUploaded = "uploaded"
+	AvatarFailed   = "failed"
+)
+
+// Outcome is what Run did with one new or updated testimonial.
+type Outcome struct {
+	Name    string `json:"name"`
+	Company string `json:"company,omitempty"`
+	// Action is ActionNew, ActionUpdated or ActionRemoved.
+	Action string `json:"action"`
+	// Avatar is AvatarUploaded, AvatarFailed or empty when no upload was
+	// attempted; Error explains a failure.
+	Avatar string `json:"avatar,omitempty"`
+	Error  string `json:"error,omitempty"`
+	// AssetID is the uploaded avatar's Contentful asset, when it is one.
+	AssetID string `json:"assetId,omitempty"`
+}
+
+// Report accounts for every scraped recommendation in a section, from
+// scrape through to the published entry.
+type Report struct {
+	SectionID string `json:"sectionId"`
+	// Scraped counts recommendations returned by LinkedIn, before filtering.
+	Scraped int `json:"scraped"`
+	// Filtered were dropped before the merge, e.g. by --min-mutuals.
+	Filtered int `json:"filtered"`
+	// Deduped matched a testimonial already stored.
+	Deduped int `json:"deduped"`
+	New     int `json:"new"`
+	// Updated counts deduped testimonials whose quote, role or company was
+	// refreshed from LinkedIn.
+	Updated int `json:"updated"`
+	// Removed counts testimonials pruned because they are gone from
+	// LinkedIn.
+	Removed          int `json:"removed"`
+	AvatarsUploaded  int `json:"avatarsUploaded"`
+	AvatarsAttempted int `json:"avatarsAttempted"`
+	AvatarsFailed    int `json:"avatarsFailed"`
+	// TranslationsFailed counts quotes kept untranslated because the
+	// translation failed. Run doesn't translate; the caller sets it.
+	TranslationsFailed int `json:"translationsFailed"`
+	// Written is the number of testimonials in the entry as written; zero
+	// when nothing was written.
+	Written int    `json:"written"`
+	EntryID string `json:"entryId,omitempty"`
+	// Published reports whether the entry was published; PublishedVersion
+	// is the version that was.
+	Published        bool `json:"published"`
+	PublishedVersion int  `json:"publishedVersion,omitempty"`
+	// ContentHash is the ContentHash of the written testimonials.
+	ContentHash string    `json:"contentHash,omitempty"`
+	Outcomes    []Outcome `json:"outcomes,omitempty"`
+}
+
+// Failures counts the items that failed without stopping the run.
+func (r *Report) Failures() int {
+	return r.AvatarsFailed + r.TranslationsFailed
+}
+
+func (r *Report) String() string {
+	return fmt.Sprintf("scraped %d, filtered %d, deduped %d, new %d, updated %d, removed %d, avatars %d/%d, written %d, published version %d",
+		r.Scraped, r.Filtered, r.Deduped, r.New, r.Updated, r.Removed, r.AvatarsUploaded, r.AvatarsAttempted, r.Written, r.PublishedVersion)
+}
+
+// Run merges opts.Recommendations into the stored testimonials, uploads
+// avatars for new ones and writes and publishes the entry. The report is
+// returned even when Run fails, with the counts reached so far.
+func Run(ctx context.Context, deps Deps, opts RunOptions) (*Report, error) {
+	logger := deps.Logger
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	store := deps.Store
+	scraped := opts.Recommendations
+	report := &Report{
+		SectionID: opts.SectionID,
+		Scraped:   len(scraped) + opts.Filtered,
+		Filtered:  opts.Filtered,
+	}
+	if len(scraped) == 0 {
+		logger.Warn("No recommendations found; LinkedIn's API may have changed")
+		return report, nil
+	}
+
+	// Truncate over-long fields before merging, so a value truncated on an
+	// earlier run still matches its scraped original. Warnings are logged
+	// below, only for entries that are written.
+	truncated := make(map[string][]FieldViolation)
+	if !opts.Strict {
+		scraped = slices.Clone(scraped)
+		for i := range scraped {
+			if violations := TruncateRecommendation(&scraped[i], opts.Limits); len(violations) > 0 {
+				truncated[truncationKey(scraped[i].Name, scraped[i].Quote)] = violations
+			}
+		}
+	}
+
+	r := &runner{
+		store:     store,
+		logger:    logger,
+		opts:      opts,
+		scraped:   scraped,
+		truncated: truncated,
+		avatars:   make(map[string]avatarUpload),
+	}
+	base := *report
+	for attempt := 0; ; attempt++ {
+		*report = base
+		err := r.sync(ctx, report)
+		if !errors.Is(err, contentful.ErrVersionConflict) {
+			return report, err
+		}
+		if attempt == maxConflictRetries {
+			return report, fmt.Errorf("entry still changed by another writer after %d retries: %w", maxConflictRetries, err)
+		}
+		logger.Info("Testimonials entry changed since it was read; fetching and merging again")
+	}
+}
+
+// runner holds the state of one Run that outlives a version conflict, so
+// the merge can be repeated against the entry as it is now.
+type runner struct {
+	store     Store
+	logger    *slog.Logger
+	opts      RunOptions
+	scraped   []linkedin.Recommendation
+	truncated map[string][]FieldViolation
+	// avatars caches uploads by scraped avatar URL, so a repeated merge
+	// doesn't upload the same avatar twice.
+	avatars map[string]avatarUpload
+}
+
+type avatarUpload struct {
+	asset contentful.UploadedAsset
+	err   error
+}
+
+// sync fetches the stored testimonials, merges the scraped ones in and
+// writes and publishes the result, filling in report. An update that
+// lost a race with another writer fails with contentful.ErrVersionConflict.
+func (r *runner) sync(ctx context.Context, report *Report) error {
+	result, err := r.store.GetTestimonials(ctx)
+	if err != nil {
+		return fmt.Errorf("contentful fetch: %w", err)
+	}
+	r.logger.Info("Fetched existing testimonials", "count", len(result.Testimonials))
+
+	// Merge (or replace if Force). AppendOnly always takes the plain
+	// append path and never touches existing entries.
+	var merged []contentful.Testimonial
+	var newIndices, changedIndices []int
+	var removed []contentful.Testimonial
+	if r.opts.Force && !r.opts.Merge.AppendOnly {
+		manual := manualTestimonials(result.Testimonials, r.scraped, r.opts.Merge.Strategy)
+		r.logger.Info("Force mode: replacing testimonials synced from LinkedIn", "kept_manual", len(manual))
+		for i, rec := range r.scraped {
+			newIndices = append(newIndices, i)
+			merged = append(merged, ToTestimonial(rec))
+		}
+		merged = append(merged, manual...)
+		report.New = len(newIndices)
+	} else {
+		existing := result.Testimonials
+		if r.opts.Prune && !r.opts.Merge.AppendOnly {
+			if r.opts.LookupFailures > 0 {
+				// A failed lookup can leave out a recommendation that is
+				// still on LinkedIn, and pruning would delete its
+				// testimonial.
+				r.logger.Warn("Skipping prune: some recommender lookups failed", "failed", r.opts.LookupFailures)
+			} else {
+				existing, removed = Reconcile(existing, r.scraped)
+				report.Removed = len(removed)
+				for _, t := range removed {
+					r.logger.Info("Removing testimonial no longer on LinkedIn", "name", t.Name)
+				}
+			}
+		}
+		merged, newIndices, changedIndices = Merge(existing, r.scraped, r.opts.Merge)
+		report.Deduped = len(r.scraped) - len(newIndices)
+		report.New = len(newIndices)
+		report.Updated = len(changedIndices)
+		for _, idx := range changedIndices {
+			r.logger.Info("Updating testimonial: quote, role or company changed on LinkedIn", "name", merged[idx].Name)
+		}
+		if len(newIndices) == 0 && reflect.DeepEqual(merged, result.Testimonials) {
+			r.logger.Info("No new recommendations to add; everything is up to date")
+			return nil
+		}
+	}
+
+	outcomes := make(map[int]*Outcome, len(newIndices)+len(changedIndices))
+	for _, idx := range newIndices {
+		outcomes[idx] = &Outcome{Action: ActionNew}
+	}
+	for _, idx := range changedIndices {
+		outcomes[idx] = &Outcome{Action: ActionUpdated}
+	}
+
+	// Fail on new and updated entries beyond Contentful's field limits
+	for _, idx := range append(slices.Clone(newIndices), changedIndices...) {
+		t := merged[idx]
+		if r.opts.Strict {
+			if violations := ValidateLengths(t, r.opts.Limits); len(violations) > 0 {
+				return fmt.Errorf("testimonial from %s: %s: %w", t.Name, violations[0], ErrFieldTooLong)
+			}
+			continue
+		}
+		for _, v := range r.truncated[truncationKey(t.Name, t.Quote)] {
+			r.logger.Warn("Truncated field", "field", v.Field, "name", t.Name, "detail", v.String())
+		}
+	}
+
+	// Skip everything when the content matches the last recorded run,
+	// unless Force asks for a write regardless
+	contentHash, err := ContentHash(merged)
+	if err != nil {
+		return fmt.Errorf("content hash: %w", err)
+	}
+	if !r.opts.Force && r.opts.LastContentHash != "" && r.opts.LastContentHash == contentHash {
+		r.logger.Info("No changes since last run; skipping update and publish")
+		return nil
+	}
+
+	r.logger.Info("Syncing recommendations", "total", len(merged), "new", len(newIndices), "updated", len(changedIndices), "removed", len(removed))
+
+	// Upload avatars for new recommendations
+	for _, idx := range newIndices {
+		t := &merged[idx]
+		if t.AvatarURL == "" {
+			continue
+		}
+		r.logger.Debug("Uploading avatar", "name", t.Name)
+		report.AvatarsAttempted++
+		upload, ok := r.avatars[t.AvatarURL]
+		if !ok {
+			upload.asset, upload.err = r.store.UploadAvatar(ctx, t.AvatarURL, contentful.AvatarOwner{
+				Name:    t.Name,
+				Role:    t.Role,
+				Company: t.Company,
+			})
+			r.avatars[t.AvatarURL] = upload
+		}
+		uploaded, err := upload.asset, upload.err
+		if err != nil {
+			r.logger.Warn("Avatar upload failed", "name", t.Name, "err", err)
+			t.AvatarURL = ""
+			report.AvatarsFailed++
+			outcomes[idx].Avatar = AvatarFailed
+			outcomes[idx].Error = err.Error()
+			continue
+		}
+		t.AvatarURL = uploaded.URL
+		report.AvatarsUploaded++
+		outcomes[idx].Avatar = AvatarUploaded
+		outcomes[idx].AssetID = uploaded.AssetID
+		r.logger.Debug("Avatar uploaded", "name", t.Name, "url", uploaded.URL, "asset", uploaded.AssetID)
+	}
+	for idx := range merged {
+		if o := outcomes[idx]; o != nil {
+			o.Name, o.Company = merged[idx].Name, merged[idx].Company
+			report.Outcomes = append(report.Outcomes, *o)
+		}
+	}
+	for _, t := range removed {
+		report.Outcomes = append(report.Outcomes, Outcome{Name: t.Name, Company: t.Company, Action: ActionRemoved})
+	}
+
+	if pending := r.store.PendingAssets(); len(pending) > 0 && r.opts.NoPublish {
+		r.logger.Info("Left avatar assets unpublished for review; their URLs work in previews but publish them before publishing the entry",
+			"count", len(pending), "assets", strings.Join(pending, ","))
+	} else if len(pending) > 0 {
+		r.logger.Info("Publishing avatars in bulk", "count", len(pending))
+		if err := r.store.PublishAssetsBulk(ctx, pending); err != nil {
+			return fmt.Errorf("publish avatars: %w", err)
+		}
+	}
+
+	// Create or update, then publish
+	var newVersion int
+	if result.EntryID == "" {
+		r.logger.Info("Creating new testimonials entry in Contentful")
+		report.EntryID, newVersion, err = r.store.CreateTestimonials(ctx, merged)
+		if err != nil {
+			return fmt.Errorf("contentful create: %w", err)
+		}
+	} else {
+		report.EntryID = result.EntryID
+		newVersion, err = r.store.UpdateTestimonials(ctx, result, merged)
+		if err != nil {
+			return fmt.Errorf("contentful update: %w", err)
+		}
+		if newVersion == result.Version {
+			r.logger.Info("Stored content already matches; skipped the update")
+		}
+	}
+	report.Written = len(merged)
+	report.ContentHash = contentHash
+
+	if r.opts.NoPublish {
+		r.logger.Info("Synced; entry left as a draft. Publish it in Contentful or with `publish`", "entry", report.EntryID, "version", newVersion)
+		return nil
+	}
+	if err := r.store.PublishEntry(ctx, report.EntryID, newVersion); err != nil {
+		return fmt.Errorf("contentful publish: %w", err)
+	}
+	report.Published = true
+	report.PublishedVersion = newVersion
+	r.logger.Info("Synced and published", "entry", report.EntryID, "version", newVersion)
+	return nil
+}
+
+// truncationKey identifies a truncated recommendation among the merged
+// testimonials.
+func truncationKey(name, quote string) string {
+	return name + "\x00" + quote
+}
@@ -0,0 +1,159 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/contentful"
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/linkedin"
+)
+
+// fakeStore is an in-memory Store holding one testimonials entry.
+type fakeStore struct {
+	result  contentful.TestimonialsResult
+	written []contentful.Testimonial
+	uploads int
+	// concurrent, when set, is written by another writer just before the
+	// next update, which then fails with a version conflict.
+	concurrent []contentful.Testimonial
+}
+
+func (s *fakeStore) GetTestimonials(ctx context.Context) (*contentful.TestimonialsResult, error) {
+	result := s.result
+	return &result, nil
+}
+
+func (s *fakeStore) CreateTestimonials(ctx context.Context, testimonials []contentful.Testimonial) (string, int, error) {
+	s.written = testimonials
+	return "entry1", 1, nil
+}
+
+func (s *fakeStore) UpdateTestimonials(ctx context.Context, result *contentful.TestimonialsResult, testimonials []contentful.Testimonial) (int, error) {
+	if s.concurrent != nil {
+		s.result.Testimonials, s.concurrent = s.concurrent, nil
+		s.result.Version++
+	}
+	if result.Version != s.result.Version {
+		return 0, fmt.Errorf("entry %s: %w", result.EntryID, contentful.ErrVersionConflict)
+	}
+	s.written = testimonials
+	return result.Version + 1, nil
+}
+
+func (s *fakeStore) PublishEntry(ctx context.Context, entryID string, version int) error {
+	return nil
+}
+
+func (s *fakeStore) UploadAvatar(ctx context.Context, imageURL string, owner contentful.AvatarOwner) (contentful.UploadedAsset, error) {
+	s.uploads++
+	return contentful.UploadedAsset{URL: imageURL}, nil
+}
+
+func (s *fakeStore) PendingAssets() []string { return nil }
+
+func (s *fakeStore) PublishAssetsBulk(ctx context.Context, ids []string) error { return nil }
+
+func TestRunForceKeepsManualTestimonials(t *testing.T) {
+	manual := contentful.Testimonial{Name: "Cy", Company: "Hooli", Quote: "Written by hand"}
+	store := &fakeStore{result: contentful.TestimonialsResult{
+		EntryID: "entry1",
+		Version: 4,
+		Testimonials: []contentful.Testimonial{
+			{Name: "Ana", Company: "Acme", Quote: "Old quote", LinkedInURL: "https://www.linkedin.com/in/ana"},
+			manual,
+			{Name: "Bo", Company: "Initech", Quote: "Gone from LinkedIn", LinkedInURL: "https://www.linkedin.com/in/bo"},
+		},
+	}}
+	scraped := []linkedin.Recommendation{
+		{Name: "Ana", Company: "Acme", Quote: "New quote", LinkedInURL: "https://www.linkedin.com/in/ana"},
+	}
+
+	report, err := Run(context.Background(), Deps{Store: store}, RunOptions{
+		Recommendations: scraped,
+		Force:           true,
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := []contentful.Testimonial{ToTestimonial(scraped[0]), manual}
+	if !reflect.DeepEqual(store.written, want) {
+		t.Errorf("written = %+v, want %+v", store.written, want)
+	}
+	if report.Written != 2 || !report.Published {
+		t.Errorf("report = %s, want 2 written and published", report)
+	}
+}
+
+func TestRunMergesAgainAfterVersionConflict(t *testing.T) {
+	ana := contentful.Testimonial{Name: "Ana", Company: "Acme", Quote: "Great", LinkedInURL: "https://www.linkedin.com/in/ana"}
+	edited := contentful.Testimonial{Name: "Cy", Company: "Hooli", Quote: "Added in the web app"}
+	store := &fakeStore{
+		result: contentful.TestimonialsResult{
+			EntryID:      "entry1",
+			Version:      4,
+			Testimonials: []contentful.Testimonial{ana},
+		},
+		concurrent: []contentful.Testimonial{ana, edited},
+	}
+	scraped := []linkedin.Recommendation{
+		{Name: "Ana", Company: "Acme", Quote: "Great", LinkedInURL: "https://www.linkedin.com/in/ana"},
+		{Name: "Bo", Company: "Initech", Quote: "Sharp", AvatarURL: "https://media.licdn.com/bo.jpg"},
+	}
+
+	report, err := Run(context.Background(), Deps{Store: store}, RunOptions{Recommendations: scraped})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := []contentful.Testimonial{ana, edited, ToTestimonial(scraped[1])}
+	if !reflect.DeepEqual(store.written, want) {
+		t.Errorf("written = %+v, want the other writer's entry kept: %+v", store.written, want)
+	}
+	if store.uploads != 1 {
+		t.Errorf("uploads = %d, want the avatar uploaded once", store.uploads)
+	}
+	if report.New != 1 || report.AvatarsUploaded != 1 || report.PublishedVersion != 6 {
+		t.Errorf("report = %s, want 1 new, 1 avatar and version 6 published", report)
+	}
+}
+
+func TestRunSkipsPruneAfterFailedLookups(t *testing.T) {
+	stored := []contentful.Testimonial{
+		{Name: "Ana", Company: "Acme", Quote: "Great", LinkedInURL: "https://www.linkedin.com/in/ana"},
+		{Name: "Bo", Company: "Initech", Quote: "Sharp", LinkedInURL: "https://www.linkedin.com/in/bo"},
+	}
+	scraped := []linkedin.Recommendation{
+		{Name: "Ana", Company: "Acme", Quote: "Great", LinkedInURL: "https://www.linkedin.com/in/ana"},
+		{Name: "Cy", Company: "Hooli", Quote: "Reliable", LinkedInURL: "https://www.linkedin.com/in/cy"},
+	}
+
+	for _, tt := range []struct {
+		name           string
+		lookupFailures int
+		wantRemoved    int
+	}{
+		{name: "no failures", wantRemoved: 1},
+		{name: "failed lookups", lookupFailures: 1, wantRemoved: 0},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeStore{result: contentful.TestimonialsResult{EntryID: "entry1", Version: 1, Testimonials: stored}}
+			report, err := Run(context.Background(), Deps{Store: store}, RunOptions{
+				Recommendations: scraped,
+				LookupFailures:  tt.lookupFailures,
+				Prune:           true,
+			})
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if report.Removed != tt.wantRemoved {
+				t.Errorf("Removed = %d, want %d", report.Removed, tt.wantRemoved)
+			}
+			if want := len(stored) + 1 - tt.wantRemoved; len(store.written) != want {
+				t.Errorf("wrote %d testimonials, want %d", len(store.written), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package sync
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/alberto-moreno-sa/linkedin-contentful-sync/internal/contentful"
+)
+
+// Stats summarizes a set of testimonials.
+type Stats struct {
+	Total           int `json:"total"`
+	WithAvatar      int `json:"withAvatar"`
+	WithLinkedInURL int `json:"withLinkedInUrl"`
+	// Companies counts distinct non-empty companies, ignoring case.
+	Companies int `json:"companies"`
+	// AvgQuoteLength and LongestQuoteLength are in characters.
+	// LongestQuoteName is who wrote the longest quote.
+	AvgQuoteLength     float64 `json:"avgQuoteLength"`
+	LongestQuoteLength int     `json:"longestQuoteLength"`
+	LongestQuoteName   string  `json:"longestQuoteName,omitempty"`
+}
+
+// ComputeStats summarizes testimonials. The zero Stats describes an empty
+// list.
+func ComputeStats(testimonials []contentful.Testimonial) Stats {
+	var s Stats
+	companies := make(map[string]bool)
+	var quoteChars int
+	for _, t := range testimonials {
+		s.Total++
+		if t.AvatarURL != "" {
+			s.WithAvatar++
+		}
+		if t.LinkedInURL != "" {
+			s.WithLinkedInURL++
+		}
+		if c := strings.ToLower(strings.TrimSpace(t.Company)); c != "" {
+			companies[c] = true
+		}
+		n := utf8.RuneCountInString(t.Quote)
+		quoteChars += n
+		if n > s.LongestQuoteLength {
+			s.LongestQuoteLength = n
+			s.LongestQuoteName = t.Name
+		}
+	}
+	s.Companies = len(companies)
+	if s.Total > 0 {
+		s.AvgQuoteLength = float64(quoteChars) / float64(s.Total)
+	}
+	return s
+}
@@ -0,0 +1,61 @@
+package translate
+
+import "strings"
+
+// languageNames maps ISO 639-1 codes and native names to the English
+// language names Gemini reports, e.g. "es" and "español" to "spanish".
+var languageNames = map[string]string{
+	"ar": "arabic",
+	"ca": "catalan",
+	"cs": "czech",
+	"da": "danish",
+	"de": "german",
+	"el": "greek",
+	"en": "english",
+	"es": "spanish",
+	"fi": "finnish",
+	"fr": "french",
+	"he": "hebrew",
+	"hi": "hindi",
+	"hu": "hungarian",
+	"it": "italian",
+	"ja": "japanese",
+	"ko": "korean",
+	"nl": "dutch",
+	"no": "norwegian",
+	"pl": "polish",
+	"pt": "portuguese",
+	"ro": "romanian",
+	"ru": "russian",
+	"sv": "swedish",
+	"tr": "turkish",
+	"uk": "ukrainian",
+	"zh": "chinese",
+
+	"deutsch":    "german",
+	"español":    "spanish",
+	"français":   "french",
+	"italiano":   "italian",
+	"nederlands": "dutch",
+	"português":  "portuguese",
+}
+
+// normalizeLanguage reduces a language code or name to a lowercase English
+// name: "es-MX", "ES" and "Spanish" all become "spanish". Unknown values
+// are only lowercased and trimmed.
+func normalizeLanguage(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if code, _, ok := strings.Cut(strings.ReplaceAll(lang, "_", "-"), "-"); ok && len(code) == 2 {
+		lang = code
+	}
+	if name, ok := languageNames[lang]; ok {
+		return name
+	}
+	return lang
+}
+
+// SameLanguage reports whether a and b name the same language, accepting
+// ISO 639-1 codes, regional tags and English or native names on either side.
+func SameLanguage(a, b string) bool {
+	return normalizeLanguage(a) == normalizeLanguage(b)
+}
@@ -0,0 +1,30 @@
+package translate
+
+import "testing"
+
+func TestSameLanguage(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{a: "English", b: "English", want: true},
+		{a: "english", b: "English", want: true},
+		{a: "Spanish", b: "es", want: true},
+		{a: "German", b: "de", want: true},
+		{a: "Spanish", b: "es-MX", want: true},
+		{a: "Portuguese", b: "pt_BR", want: true},
+		{a: "Spanish", b: "español", want: true},
+		{a: " French ", b: "FR", want: true},
+		{a: "Spanish", b: "de", want: false},
+		{a: "English", b: "Spanish", want: false},
+		{a: "Klingon", b: "klingon", want: true},
+	}
+	for _, tt := range tests {
+		if got := SameLanguage(tt.a, tt.b); got != tt.want {
+			t.Errorf("SameLanguage(%q, %q) = %t, want %t", tt.a, tt.b, got, tt.want)
+		}
+		if got := SameLanguage(tt.b, tt.a); got != tt.want {
+			t.Errorf("SameLanguage(%q, %q) = %t, want %t", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,416 @@
+// Package translate translates quotes with Google Gemini. It is the tool's
+// only translation path: go-service-kit's gemini.Translate is deliberately
+// not used, so the prompt, model and retry policy are all set here.
+package translate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/genai"
+)
+
+// Model is the Gemini model used for translation.
+const Model = "gemini-2.5-flash"
+
+// DefaultTargetLang is the language quotes are translated to unless the
+// caller asks for another.
+const DefaultTargetLang = "English"
+
+const (
+	maxAttempts = 3
+	baseBackoff = time.Second
+	// batchFallbackConcurrency is how many texts TranslateBatch translates
+	// at once when it falls back to one call per text.
+	batchFallbackConcurrency = 4
+)
+
+// TranslateResult describes a single translation call.
+type TranslateResult struct {
+	Text string
+	// SourceLang is the detected source language, empty when the model
+	// does not report it.
+	SourceLang   string
+	Model        string
+	Elapsed      time.Duration
+	PromptTokens int
+	OutputTokens int
+}
+
+// Translate translates text to targetLang using Google Gemini, retrying
+// failed calls with exponential backoff.
+func Translate(ctx context.Context, apiKey, text, targetLang string) (string, error) {
+	result, err := TranslateDetailed(ctx, apiKey, text, targetLang)
+	if err != nil {
+		return "", err
+	}
+	return result.Text, nil
+}
+
+// ToEnglish translates text to English. It is shorthand for Translate with
+// DefaultTargetLang.
+func ToEnglish(ctx context.Context, apiKey, text string) (string, error) {
+	return Translate(ctx, apiKey, text, DefaultTargetLang)
+}
+
+// TranslateDetailed is like Translate but also reports the model used,
+// elapsed time (including retries), and token usage.
+func TranslateDetailed(ctx context.Context, apiKey, text, targetLang string) (*TranslateResult, error) {
+	prompt := fmt.Sprintf("Translate the following text to %s. Return only the translated text, nothing else.", targetLang)
+	config := &genai.GenerateContentConfig{
+		SystemInstruction: &genai.Content{
+			Parts: []*genai.Part{
+				{Text: prompt},
+			},
+		},
+	}
+
+	result, resp, err := generate(ctx, apiKey, text, config)
+	if err != nil {
+		return nil, err
+	}
+	result.Text = strings.TrimSpace(resp.Text())
+	return result, nil
+}
+
+// DetectAndTranslate detects the language of text and translates it to
+// targetLang in a single Gemini call, asking for a JSON reply with both.
+// SourceLang is set to the detected language name.
+func DetectAndTranslate(ctx context.Context, apiKey, text, targetLang string) (TranslateResult, error) {
+	prompt := fmt.Sprintf("Detect the language of the following text and translate it to %s. "+
+		"Reply with JSON: detectedLanguage is the English name of the source language, "+
+		"translatedText is the translation (the original text if it is already in %s).", targetLang, targetLang)
+	config := &genai.GenerateContentConfig{
+		SystemInstruction: &genai.Content{
+			Parts: []*genai.Part{
+				{Text: prompt},
+			},
+		},
+		ResponseMIMEType: "application/json",
+		ResponseSchema: &genai.Schema{
+			Type: genai.TypeObject,
+			Properties: map[string]*genai.Schema{
+				"detectedLanguage": {Type: genai.TypeString},
+				"translatedText":   {Type: genai.TypeString},
+			},
+			Required: []string{"detectedLanguage", "translatedText"},
+		},
+	}
+
+	result, resp, err := generate(ctx, apiKey, text, config)
+	if err != nil {
+		return TranslateResult{}, err
+	}
+	var reply struct {
+		DetectedLanguage string `json:"detectedLanguage"`
+		TranslatedText   string `json:"translatedText"`
+	}
+	if err := json.Unmarshal([]byte(resp.Text()), &reply); err != nil {
+		return TranslateResult{}, fmt.Errorf("decode gemini reply: %w", err)
+	}
+	if reply.TranslatedText == "" {
+		return TranslateResult{}, fmt.Errorf("gemini reply has no translatedText")
+	}
+	result.Text = strings.TrimSpace(reply.TranslatedText)
+	result.SourceLang = strings.TrimSpace(reply.DetectedLanguage)
+	return *result, nil
+}
+
+// detections caches Detect results by text for the life of the process,
+// so a quote seen in several sections is only detected once per run.
+var detections = struct {
+	mu    sync.Mutex
+	langs map[string]string
+}{langs: make(map[string]string)}
+
+// Detect returns the English name of text's language, e.g. "Spanish".
+func Detect(ctx context.Context, apiKey, text string) (string, error) {
+	detections.mu.Lock()
+	lang, ok := detections.langs[text]
+	detections.mu.Unlock()
+	if ok {
+		return lang, nil
+	}
+
+	config := &genai.GenerateContentConfig{
+		SystemInstruction: &genai.Content{
+			Parts: []*genai.Part{
+				{Text: "Identify the language of the following text. Reply with JSON: language is its English name."},
+			},
+		},
+		ResponseMIMEType: "application/json",
+		ResponseSchema: &genai.Schema{
+			Type: genai.TypeObject,
+			Properties: map[string]*genai.Schema{
+				"language": {Type: genai.TypeString},
+			},
+			Required: []string{"language"},
+		},
+	}
+	_, resp, err := generate(ctx, apiKey, text, config)
+	if err != nil {
+		return "", err
+	}
+	var reply struct {
+		Language string `json:"language"`
+	}
+	if err := json.Unmarshal([]byte(resp.Text()), &reply); err != nil {
+		return "", fmt.Errorf("decode gemini reply: %w", err)
+	}
+	lang = strings.TrimSpace(reply.Language)
+
+	detections.mu.Lock()
+	detections.langs[text] = lang
+	detections.mu.Unlock()
+	return lang, nil
+}
+
+// TranslateIfNeeded is like TranslateDetailed but first detects text's
+// language, returning text unchanged when it is already in targetLang.
+// SourceLang is always set to the detected language.
+func TranslateIfNeeded(ctx context.Context, apiKey, text, targetLang string) (*TranslateResult, error) {
+	start := time.Now()
+	lang, err := Detect(ctx, apiKey, text)
+	if err != nil {
+		return nil, fmt.Errorf("detect language: %w", err)
+	}
+	if SameLanguage(lang, targetLang) {
+		return &TranslateResult{Text: text, SourceLang: lang, Model: Model, Elapsed: time.Since(start)}, nil
+	}
+
+	result, err := TranslateDetailed(ctx, apiKey, text, targetLang)
+	if err != nil {
+		return nil, err
+	}
+	result.SourceLang = lang
+	result.Elapsed = time.Since(start)
+	return result, nil
+}
+
+// generate sends text to Gemini with config. It is a variable so tests can
+// stand in for Gemini.
+var generate = generateContent
+
+// generateContent sends text to Gemini with config, retrying failed calls
+// with exponential backoff. The returned result carries the model, elapsed
+// time and token usage; callers fill in the text from resp.
+func generateContent(ctx context.Context, apiKey, text string, config *genai.GenerateContentConfig) (*TranslateResult, *genai.GenerateContentResponse, error) {
+	client, err := genai.NewClient(ctx, &genai.ClientConfig{
+		APIKey:  apiKey,
+		Backend: genai.BackendGeminiAPI,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("gemini client: %w", err)
+	}
+
+	start := time.Now()
+	var lastErr error
+	backoff := baseBackoff
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		resp, err := client.Models.GenerateContent(ctx, Model, genai.Text(text), config)
+		if err == nil {
+			result := &TranslateResult{
+				Model:   Model,
+				Elapsed: time.Since(start),
+			}
+			if resp.ModelVersion != "" {
+				result.Model = resp.ModelVersion
+			}
+			if resp.UsageMetadata != nil {
+				result.PromptTokens = int(resp.UsageMetadata.PromptTokenCount)
+				result.OutputTokens = int(resp.UsageMetadata.CandidatesTokenCount)
+			}
+			return result, resp, nil
+		}
+		lastErr = err
+
+		if attempt == maxAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return nil, nil, fmt.Errorf("gemini generate after %d attempts: %w", maxAttempts, lastErr)
+}
+
+// TranslateBatch translates all texts to targetLang in a single Gemini
+// call and returns the translations in input order. Texts already in
+// targetLang come back unchanged. When the reply doesn't have exactly one
+// translation per text, it falls back to translating them one by one.
+func TranslateBatch(ctx context.Context, apiKey string, texts []string, targetLang string) ([]string, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+	items := make([]batchItem, len(texts))
+	for i, text := range texts {
+		items[i] = batchItem{N: i + 1, Text: text}
+	}
+	input, err := json.Marshal(items)
+	if err != nil {
+		return nil, fmt.Errorf("marshal batch: %w", err)
+	}
+
+	prompt := fmt.Sprintf("Translate the text of every numbered item in the following JSON array to %s. "+
+		"Keep a text unchanged if it is already in %s. "+
+		"Reply with JSON: translations lists each item once, with its number n and its translated text.", targetLang, targetLang)
+	config := &genai.GenerateContentConfig{
+		SystemInstruction: &genai.Content{
+			Parts: []*genai.Part{
+				{Text: prompt},
+			},
+		},
+		ResponseMIMEType: "application/json",
+		ResponseSchema: &genai.Schema{
+			Type: genai.TypeObject,
+			Properties: map[string]*genai.Schema{
+				"translations": {
+					Type: genai.TypeArray,
+					Items: &genai.Schema{
+						Type: genai.TypeObject,
+						Properties: map[string]*genai.Schema{
+							"n":    {Type: genai.TypeInteger},
+							"text": {Type: genai.TypeString},
+						},
+						Required: []string{"n", "text"},
+					},
+				},
+			},
+			Required: []string{"translations"},
+		},
+	}
+
+	_, resp, err := generate(ctx, apiKey, string(input), config)
+	if err != nil {
+		return nil, err
+	}
+	translations, parseErr := parseBatchReply(resp.Text(), len(texts))
+	if parseErr == nil {
+		return translations, nil
+	}
+
+	translations, errs := TranslateAll(ctx, apiKey, texts, targetLang, batchFallbackConcurrency)
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("batch reply unusable (%v), and translating one by one failed: %w", parseErr, err)
+	}
+	return translations, nil
+}
+
+// batchItem is one numbered text in a TranslateBatch request or reply.
+type batchItem struct {
+	N    int    `json:"n"`
+	Text string `json:"text"`
+}
+
+// parseBatchReply decodes a TranslateBatch reply into count translations
+// ordered by number. Every number from 1 to count must appear exactly once
+// with a non-empty text.
+func parseBatchReply(reply string, count int) ([]string, error) {
+	var decoded struct {
+		Translations []batchItem `json:"translations"`
+	}
+	if err := json.Unmarshal([]byte(reply), &decoded); err != nil {
+		return nil, fmt.Errorf("decode gemini reply: %w", err)
+	}
+	if len(decoded.Translations) != count {
+		return nil, fmt.Errorf("got %d translations for %d texts", len(decoded.Translations), count)
+	}
+	translations := make([]string, count)
+	for _, item := range decoded.Translations {
+		if item.N < 1 || item.N > count {
+			return nil, fmt.Errorf("translation numbered %d is out of range", item.N)
+		}
+		if translations[item.N-1] != "" {
+			return nil, fmt.Errorf("translation %d appears twice", item.N)
+		}
+		text := strings.TrimSpace(item.Text)
+		if text == "" {
+			return nil, fmt.Errorf("translation %d is empty", item.N)
+		}
+		translations[item.N-1] = text
+	}
+	return translations, nil
+}
+
+// TranslateAll translates texts concurrently using at most concurrency workers.
+// Results and errors are returned in input order; a failed item has an empty
+// result and a non-nil error at its index, without aborting the others.
+func TranslateAll(ctx context.Context, apiKey string, texts []string, targetLang string, concurrency int) ([]string, []error) {
+	detailed, errs := TranslateAllDetailed(ctx, apiKey, texts, targetLang, concurrency)
+	results := make([]string, len(texts))
+	for i, r := range detailed {
+		if r != nil {
+			results[i] = r.Text
+		}
+	}
+	return results, errs
+}
+
+// TranslateAllDetailed is like TranslateAll but returns the full result of
+// each call. A failed item has a nil result.
+func TranslateAllDetailed(ctx context.Context, apiKey string, texts []string, targetLang string, concurrency int) ([]*TranslateResult, []error) {
+	return runAll(texts, concurrency, func(text string) (*TranslateResult, error) {
+		return TranslateDetailed(ctx, apiKey, text, targetLang)
+	})
+}
+
+// TranslateIfNeededAll runs TranslateIfNeeded over texts concurrently
+// using at most concurrency workers. A failed item has a nil result and a
+// non-nil error at its index.
+func TranslateIfNeededAll(ctx context.Context, apiKey string, texts []string, targetLang string, concurrency int) ([]*TranslateResult, []error) {
+	return runAll(texts, concurrency, func(text string) (*TranslateResult, error) {
+		return TranslateIfNeeded(ctx, apiKey, text, targetLang)
+	})
+}
+
+// DetectAndTranslateAll runs DetectAndTranslate over texts concurrently
+// using at most concurrency workers. A failed item has a nil result and a
+// non-nil error at its index.
+func DetectAndTranslateAll(ctx context.Context, apiKey string, texts []string, targetLang string, concurrency int) ([]*TranslateResult, []error) {
+	return runAll(texts, concurrency, func(text string) (*TranslateResult, error) {
+		result, err := DetectAndTranslate(ctx, apiKey, text, targetLang)
+		if err != nil {
+			return nil, err
+		}
+		return &result, nil
+	})
+}
+
+// runAll calls fn for every text using at most concurrency workers and
+// returns results and errors in input order.
+func runAll(texts []string, concurrency int, fn func(string) (*TranslateResult, error)) ([]*TranslateResult, []error) {
+	results := make([]*TranslateResult, len(texts))
+	errs := make([]error, len(texts))
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i], errs[i] = fn(texts[i])
+			}
+		}()
+	}
+
+	for i := range texts {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results, errs
+}
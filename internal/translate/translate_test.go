@@ -0,0 +1,191 @@
+package translate
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/genai"
+)
+
+// stubGenerate replaces generate for the duration of the test with fn,
+// which returns the reply text for a call.
+func stubGenerate(t *testing.T, fn func(text string, config *genai.GenerateContentConfig) (string, error)) {
+	t.Helper()
+	orig := generate
+	generate = func(_ context.Context, _, text string, config *genai.GenerateContentConfig) (*TranslateResult, *genai.GenerateContentResponse, error) {
+		reply, err := fn(text, config)
+		if err != nil {
+			return nil, nil, err
+		}
+		resp := &genai.GenerateContentResponse{
+			Candidates: []*genai.Candidate{{Content: genai.NewContentFromText(reply, genai.RoleModel)}},
+		}
+		return &TranslateResult{Model: Model}, resp, nil
+	}
+	t.Cleanup(func() { generate = orig })
+}
+
+func TestParseBatchReply(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   string
+		count   int
+		want    []string
+		wantErr string
+	}{
+		{
+			name:  "in order",
+			reply: `{"translations":[{"n":1,"text":"one"},{"n":2,"text":"two"}]}`,
+			count: 2,
+			want:  []string{"one", "two"},
+		},
+		{
+			name:  "out of order",
+			reply: `{"translations":[{"n":3,"text":"three"},{"n":1,"text":"one"},{"n":2,"text":"two"}]}`,
+			count: 3,
+			want:  []string{"one", "two", "three"},
+		},
+		{
+			name:  "trims text",
+			reply: `{"translations":[{"n":1,"text":"  one\n"}]}`,
+			count: 1,
+			want:  []string{"one"},
+		},
+		{
+			name:    "missing index",
+			reply:   `{"translations":[{"n":1,"text":"one"},{"n":3,"text":"three"}]}`,
+			count:   2,
+			wantErr: "out of range",
+		},
+		{
+			name:    "duplicate index",
+			reply:   `{"translations":[{"n":1,"text":"one"},{"n":1,"text":"uno"}]}`,
+			count:   2,
+			wantErr: "appears twice",
+		},
+		{
+			name:    "too few lines",
+			reply:   `{"translations":[{"n":1,"text":"one"}]}`,
+			count:   2,
+			wantErr: "got 1 translations for 2 texts",
+		},
+		{
+			name:    "extra lines",
+			reply:   `{"translations":[{"n":1,"text":"one"},{"n":2,"text":"two"},{"n":3,"text":"three"}]}`,
+			count:   2,
+			wantErr: "got 3 translations for 2 texts",
+		},
+		{
+			name:    "empty text",
+			reply:   `{"translations":[{"n":1,"text":" "}]}`,
+			count:   1,
+			wantErr: "translation 1 is empty",
+		},
+		{
+			name:    "not JSON",
+			reply:   "1. one",
+			count:   1,
+			wantErr: "decode gemini reply",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseBatchReply(tt.reply, tt.count)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseBatchReply() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseBatchReply() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseBatchReply() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranslateBatchFallsBackOnCountMismatch(t *testing.T) {
+	var mu sync.Mutex
+	var single []string
+	stubGenerate(t, func(text string, config *genai.GenerateContentConfig) (string, error) {
+		if config.ResponseMIMEType == "application/json" {
+			// The batch call: one translation short.
+			return `{"translations":[{"n":1,"text":"one"}]}`, nil
+		}
+		mu.Lock()
+		single = append(single, text)
+		mu.Unlock()
+		return "translated " + text, nil
+	})
+
+	got, err := TranslateBatch(context.Background(), "key", []string{"uno", "dos"}, "English")
+	if err != nil {
+		t.Fatalf("TranslateBatch() error = %v", err)
+	}
+	want := []string{"translated uno", "translated dos"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TranslateBatch() = %q, want %q", got, want)
+	}
+	if len(single) != 2 {
+		t.Errorf("fallback made %d single calls, want 2", len(single))
+	}
+}
+
+func TestTranslateBatchUsesBatchReply(t *testing.T) {
+	calls := 0
+	stubGenerate(t, func(text string, config *genai.GenerateContentConfig) (string, error) {
+		calls++
+		return `{"translations":[{"n":2,"text":"two"},{"n":1,"text":"one"}]}`, nil
+	})
+
+	got, err := TranslateBatch(context.Background(), "key", []string{"uno", "dos"}, "English")
+	if err != nil {
+		t.Fatalf("TranslateBatch() error = %v", err)
+	}
+	if want := []string{"one", "two"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TranslateBatch() = %q, want %q", got, want)
+	}
+	if calls != 1 {
+		t.Errorf("made %d Gemini calls, want 1", calls)
+	}
+}
+
+func TestEntryPointsBuildIdenticalRequests(t *testing.T) {
+	type request struct {
+		text   string
+		config *genai.GenerateContentConfig
+	}
+	var requests []request
+	stubGenerate(t, func(text string, config *genai.GenerateContentConfig) (string, error) {
+		if config.ResponseSchema != nil {
+			// Detect, run first by TranslateIfNeeded.
+			return `{"language":"Spanish"}`, nil
+		}
+		requests = append(requests, request{text, config})
+		return "Great colleague", nil
+	})
+
+	const quote = "Gran compañero de trabajo"
+	if _, err := ToEnglish(context.Background(), "key", quote); err != nil {
+		t.Fatalf("ToEnglish() error = %v", err)
+	}
+	// scrape and translate reach Gemini through TranslateIfNeeded.
+	if _, err := TranslateIfNeeded(context.Background(), "key", quote, DefaultTargetLang); err != nil {
+		t.Fatalf("TranslateIfNeeded() error = %v", err)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("got %d translate requests, want 2", len(requests))
+	}
+	if !reflect.DeepEqual(requests[0], requests[1]) {
+		t.Errorf("requests differ:\nToEnglish:         %q %+v\nTranslateIfNeeded: %q %+v",
+			requests[0].text, requests[0].config.SystemInstruction.Parts[0],
+			requests[1].text, requests[1].config.SystemInstruction.Parts[0])
+	}
+}